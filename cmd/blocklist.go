@@ -0,0 +1,395 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/scanner"
+	"github.com/spf13/cobra"
+)
+
+// blocklistCmd groups the commands that inspect and maintain blocklists
+var blocklistCmd = &cobra.Command{
+	Use:   "blocklist",
+	Short: "Inspect, validate, convert and refresh blocklists",
+	Long: `The blocklist commands work on the same sources as scan: the --blocklist
+flags, the blocklists in a --config file, or the default Wiz Shai-Hulud list.
+
+Examples:
+  hulud-scan blocklist show
+  hulud-scan blocklist search @ctrl/tinycolor@4.1.1
+  hulud-scan blocklist validate ./internal-blocklist.csv
+  hulud-scan blocklist convert ./internal-blocklist.csv --to yaml
+  hulud-scan blocklist update`,
+}
+
+// blocklistShowCmd lists the loaded entries
+var blocklistShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show blocklist sources, entry counts and cache age",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runBlocklistShow(cmd, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// blocklistSearchCmd looks up one package
+var blocklistSearchCmd = &cobra.Command{
+	Use:   "search <package[@version]>",
+	Short: "Search the blocklist for a package",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runBlocklistSearch(cmd, os.Stdout, args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// blocklistValidateCmd checks a blocklist file for problems
+var blocklistValidateCmd = &cobra.Command{
+	Use:   "validate <file>",
+	Short: "Report malformed rows in a blocklist file, with line numbers",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runBlocklistValidate(os.Stdout, args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// blocklistConvertCmd rewrites a blocklist in another format
+var blocklistConvertCmd = &cobra.Command{
+	Use:   "convert <file-or-url>",
+	Short: "Convert a blocklist between CSV, JSON, YAML and OSV",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runBlocklistConvert(cmd, args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// blocklistUpdateCmd refreshes cached copies of remote sources
+var blocklistUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Download every remote blocklist again and refresh the cache",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runBlocklistUpdate(cmd, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(blocklistCmd)
+	blocklistCmd.AddCommand(blocklistShowCmd, blocklistSearchCmd, blocklistValidateCmd,
+		blocklistConvertCmd, blocklistUpdateCmd)
+
+	addBlocklistFlags(blocklistCmd)
+
+	blocklistShowCmd.Flags().Bool("summary", false, "Only show sources and counts, not every entry")
+
+	blocklistConvertCmd.Flags().String("to", scanner.FormatNativeJSON,
+		"Output format ("+strings.Join(scanner.WriteFormats(), ", ")+")")
+	blocklistConvertCmd.Flags().StringP("output", "o", "", "Write to a file instead of stdout")
+}
+
+// runBlocklistShow prints each source with its entry count and cache age, then the merged entries
+func runBlocklistShow(cmd *cobra.Command, w io.Writer) error {
+	sources, err := resolveBlocklistSources(cmd)
+	if err != nil {
+		return err
+	}
+	loader, err := newBlocklistLoader(cmd)
+	if err != nil {
+		return err
+	}
+	summary, _ := cmd.Flags().GetBool("summary")
+
+	lists := make([]*scanner.Blocklist, 0, len(sources))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SOURCE\tENTRIES\tCACHE")
+	for _, source := range sources {
+		list, err := loader.LoadSource(source)
+		if err != nil {
+			return fmt.Errorf("blocklist %s: %w", source.Location, err)
+		}
+		lists = append(lists, list)
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", source.Location, len(list.Entries), cacheAge(source.Location, loader.CacheDir))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	merged := scanner.MergeBlocklists(lists...)
+	fmt.Fprintf(w, "\nTotal: %d unique entries across %d packages (%s)\n",
+		len(merged.Entries), len(merged.Index), severityCounts(merged.Entries))
+
+	if summary {
+		return nil
+	}
+
+	entries := append([]scanner.BlocklistEntry(nil), merged.Entries...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].PackageName < entries[j].PackageName
+	})
+
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PACKAGE\tVERSIONS\tSEVERITY\tIDS")
+	for i := range entries {
+		entry := &entries[i]
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", entry.PackageName, entry.Affected(), entry.Severity,
+			strings.Join(entry.Identifiers, ", "))
+	}
+	return tw.Flush()
+}
+
+// runBlocklistSearch prints every entry for a package, or a verdict for package@version
+func runBlocklistSearch(cmd *cobra.Command, w io.Writer, spec string) error {
+	sources, err := resolveBlocklistSources(cmd)
+	if err != nil {
+		return err
+	}
+	loader, err := newBlocklistLoader(cmd)
+	if err != nil {
+		return err
+	}
+
+	blocklist, err := loader.LoadAll(sources)
+	if err != nil {
+		return fmt.Errorf("failed to load blocklist: %w", err)
+	}
+
+	name, version := splitPackageSpec(spec)
+
+	if version != "" {
+		if entry := blocklist.IsBlocked(name, version); entry != nil {
+			fmt.Fprintf(w, "🚨 %s@%s is blocklisted\n\n", name, version)
+			printBlocklistEntry(w, entry)
+		} else {
+			fmt.Fprintf(w, "✅ %s@%s is not blocklisted\n", name, version)
+		}
+		return nil
+	}
+
+	indices := blocklist.Index[name]
+	if len(indices) == 0 {
+		// No exact match: fall back to a substring search over package names
+		for i, entry := range blocklist.Entries {
+			if strings.Contains(entry.PackageName, name) {
+				indices = append(indices, i)
+			}
+		}
+	}
+
+	if len(indices) == 0 {
+		fmt.Fprintf(w, "No blocklist entries match %q\n", name)
+		return nil
+	}
+
+	fmt.Fprintf(w, "Found %d entr%s matching %q:\n\n", len(indices), plural(len(indices), "y", "ies"), name)
+	for _, idx := range indices {
+		printBlocklistEntry(w, &blocklist.Entries[idx])
+	}
+	return nil
+}
+
+// runBlocklistValidate reports problems in one blocklist file
+func runBlocklistValidate(w io.Writer, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read blocklist: %w", err)
+	}
+
+	issues := scanner.ValidateBlocklist(path, data)
+	if len(issues) == 0 {
+		fmt.Fprintf(w, "✅ %s: no problems found (%s format)\n", path, scanner.DetectFormat(path, data))
+		return nil
+	}
+
+	for _, issue := range issues {
+		fmt.Fprintf(w, "%s: %s\n", path, issue)
+	}
+	return fmt.Errorf("%d problem(s) found in %s", len(issues), path)
+}
+
+// runBlocklistConvert loads a blocklist in any supported format and writes it in another
+func runBlocklistConvert(cmd *cobra.Command, location string) error {
+	format, _ := cmd.Flags().GetString("to")
+	outputPath, _ := cmd.Flags().GetString("output")
+	loader, err := newBlocklistLoader(cmd)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	// Convert what the list says, without the defaults a scan would fill in
+	blocklist, err := loader.LoadRaw(configuredSource(cfg, location))
+	if err != nil {
+		return fmt.Errorf("failed to load blocklist: %w", err)
+	}
+
+	skipped, err := writeConvertedBlocklist(outputPath, blocklist, format)
+	if err != nil {
+		return err
+	}
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "⚠️  Skipped %d entr%s with version ranges, which %s cannot express\n",
+			skipped, plural(skipped, "y", "ies"), format)
+	}
+	if outputPath != "" {
+		fmt.Fprintf(os.Stderr, "✅ Wrote %d entries to %s\n", len(blocklist.Entries)-skipped, outputPath)
+	}
+	return nil
+}
+
+// writeConvertedBlocklist writes a blocklist to outputPath, or to stdout when it is empty
+func writeConvertedBlocklist(outputPath string, blocklist *scanner.Blocklist, format string) (int, error) {
+	if outputPath == "" {
+		return scanner.WriteBlocklist(os.Stdout, blocklist, format)
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create %s: %w", outputPath, err)
+	}
+
+	skipped, err := scanner.WriteBlocklist(file, blocklist, format)
+	if err != nil {
+		_ = file.Close()
+		return 0, err
+	}
+
+	if err := file.Close(); err != nil {
+		return 0, fmt.Errorf("failed to close %s: %w", outputPath, err)
+	}
+	return skipped, nil
+}
+
+// runBlocklistUpdate re-downloads every remote source into the cache
+// Every source is attempted even if an earlier one fails.
+func runBlocklistUpdate(cmd *cobra.Command, w io.Writer) error {
+	sources, err := resolveBlocklistSources(cmd)
+	if err != nil {
+		return err
+	}
+	loader, err := newBlocklistLoader(cmd)
+	if err != nil {
+		return err
+	}
+	if loader.CacheDir == "" {
+		return fmt.Errorf("blocklist update needs a --cache-dir")
+	}
+
+	failed := 0
+	for _, source := range sources {
+		if !scanner.IsRemote(source.Location) {
+			fmt.Fprintf(w, "⏭️  %s: local file, nothing to update\n", source.Location)
+			continue
+		}
+
+		list, err := loader.Refresh(source)
+		if err != nil {
+			fmt.Fprintf(w, "❌ %s: %v\n", source.Location, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(w, "✅ %s: %d entries\n", source.Location, len(list.Entries))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d blocklist(s) could not be updated", failed)
+	}
+	return nil
+}
+
+// printBlocklistEntry prints the details of one entry
+func printBlocklistEntry(w io.Writer, entry *scanner.BlocklistEntry) {
+	fmt.Fprintf(w, "%s %s\n", entry.PackageName, entry.Affected())
+	fmt.Fprintf(w, "   Severity: %s\n", entry.Severity)
+	if entry.Reason != "" {
+		fmt.Fprintf(w, "   Reason: %s\n", entry.Reason)
+	}
+	if len(entry.Identifiers) > 0 {
+		fmt.Fprintf(w, "   IDs: %s\n", strings.Join(entry.Identifiers, ", "))
+	}
+	if len(entry.FixedIn) > 0 {
+		fmt.Fprintf(w, "   Fixed in: %s\n", strings.Join(entry.FixedIn, ", "))
+	}
+	if len(entry.Tags) > 0 {
+		fmt.Fprintf(w, "   Tags: %s\n", strings.Join(entry.Tags, ", "))
+	}
+	for _, ref := range entry.References {
+		fmt.Fprintf(w, "   Link: %s\n", ref)
+	}
+	if len(entry.Sources) > 0 {
+		fmt.Fprintf(w, "   Source: %s\n", strings.Join(entry.Sources, ", "))
+	}
+	fmt.Fprintln(w)
+}
+
+// cacheAge describes how old the cached copy of a source is
+func cacheAge(location, cacheDir string) string {
+	if !scanner.IsRemote(location) {
+		return "local file"
+	}
+	cachedAt, ok := scanner.CachedAt(location, cacheDir)
+	if !ok {
+		return "not cached"
+	}
+	return fmt.Sprintf("cached %s ago", time.Since(cachedAt).Round(time.Second))
+}
+
+// severityCounts formats entry counts per severity, most severe first
+func severityCounts(entries []scanner.BlocklistEntry) string {
+	counts := make(map[scanner.Severity]int)
+	for _, entry := range entries {
+		counts[entry.Severity]++
+	}
+
+	parts := make([]string, 0, 5)
+	for _, severity := range []scanner.Severity{scanner.SeverityCritical, scanner.SeverityHigh,
+		scanner.SeverityMedium, scanner.SeverityLow, scanner.SeverityInfo} {
+		if counts[severity] > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %d", severity, counts[severity]))
+		}
+	}
+	if len(parts) == 0 {
+		return "empty"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// splitPackageSpec splits "name@version" (including "@scope/name@version")
+func splitPackageSpec(spec string) (name, version string) {
+	if i := strings.LastIndex(spec, "@"); i > 0 {
+		return spec[:i], spec[i+1:]
+	}
+	return spec, ""
+}
+
+// plural picks a word ending for a count
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
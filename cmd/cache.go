@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/scanner"
+	"github.com/spf13/cobra"
+)
+
+// cacheCmd groups the commands that manage the blocklist cache
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the blocklist cache",
+	Long: `The cache holds downloaded blocklists so scans work without re-downloading
+them, and with --offline, without any network access at all.
+
+Examples:
+  hulud-scan cache list
+  hulud-scan cache verify
+  hulud-scan cache clear --source https://example.com/blocklist.csv
+
+  # On a connected machine
+  hulud-scan blocklist update
+  hulud-scan cache export hulud-cache.tar.gz
+
+  # Across the air gap
+  hulud-scan cache import hulud-cache.tar.gz
+  hulud-scan scan . --offline`,
+}
+
+// cacheListCmd describes every cached blocklist
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List cached blocklists with their source, size, entry count, age and ETag",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runCacheList(cmd, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// cacheClearCmd removes cached blocklists
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove every cached blocklist, or only one source with --source",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runCacheClear(cmd, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// cacheVerifyCmd re-parses every cached blocklist
+var cacheVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Re-parse every cached blocklist and report corrupted copies",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runCacheVerify(cmd, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// cacheExportCmd bundles the cache into a tarball
+var cacheExportCmd = &cobra.Command{
+	Use:   "export <file.tar.gz>",
+	Short: "Bundle the cache directory into a tarball",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runCacheExport(cmd, args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// cacheImportCmd restores a tarball made by cache export
+var cacheImportCmd = &cobra.Command{
+	Use:   "import <file.tar.gz>",
+	Short: "Restore cached blocklists from a tarball made by cache export",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runCacheImport(cmd, args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheListCmd, cacheClearCmd, cacheVerifyCmd, cacheExportCmd, cacheImportCmd)
+
+	cacheCmd.PersistentFlags().String("cache-dir", defaultCacheDir(), "Cache directory for downloaded blocklists")
+	cacheClearCmd.Flags().String("source", "", "Only remove the cached copy of this blocklist URL")
+}
+
+// runCacheList prints one row per cached blocklist
+func runCacheList(cmd *cobra.Command, w io.Writer) error {
+	cacheDir, _ := cmd.Flags().GetString("cache-dir")
+
+	entries, err := scanner.ListCache(cacheDir)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Fprintf(w, "Cache %s is empty\n", cacheDir)
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SOURCE\tSIZE\tENTRIES\tAGE\tETAG\tSIGNED")
+	for _, entry := range entries {
+		source := entry.URL
+		if source == "" {
+			source = "(unknown) " + entry.Path
+		}
+		count := fmt.Sprint(entry.Entries)
+		if entry.Err != nil {
+			count = "corrupt"
+		}
+		signed := "no"
+		if entry.Signed {
+			signed = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", source, formatSize(entry.Size), count,
+			time.Since(entry.CachedAt).Round(time.Second), orDash(entry.ETag), signed)
+	}
+	return tw.Flush()
+}
+
+// runCacheClear removes the whole cache or one source's copy
+func runCacheClear(cmd *cobra.Command, w io.Writer) error {
+	cacheDir, _ := cmd.Flags().GetString("cache-dir")
+	source, _ := cmd.Flags().GetString("source")
+
+	removed, err := scanner.ClearCache(cacheDir, source)
+	if err != nil {
+		return err
+	}
+
+	if source != "" && removed == 0 {
+		return fmt.Errorf("%s is not cached in %s", source, cacheDir)
+	}
+	fmt.Fprintf(w, "🗑️  Removed %d cache file(s) from %s\n", removed, cacheDir)
+	return nil
+}
+
+// runCacheVerify re-parses every cached copy and fails if any is corrupted
+func runCacheVerify(cmd *cobra.Command, w io.Writer) error {
+	cacheDir, _ := cmd.Flags().GetString("cache-dir")
+
+	entries, err := scanner.ListCache(cacheDir)
+	if err != nil {
+		return err
+	}
+
+	corrupt := 0
+	for _, entry := range entries {
+		source := entry.URL
+		if source == "" {
+			source = entry.Path
+		}
+		if entry.Err != nil {
+			corrupt++
+			fmt.Fprintf(w, "❌ %s: %v\n", source, entry.Err)
+			continue
+		}
+		fmt.Fprintf(w, "✅ %s: %d entr%s\n", source, entry.Entries, plural(entry.Entries, "y", "ies"))
+	}
+
+	if corrupt > 0 {
+		return fmt.Errorf("%d of %d cached blocklist(s) are corrupted; run `hulud-scan cache clear` and download them again",
+			corrupt, len(entries))
+	}
+	fmt.Fprintf(w, "\nAll %d cached blocklist(s) parse cleanly\n", len(entries))
+	return nil
+}
+
+// formatSize renders a byte count for humans
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
+
+// orDash shows a placeholder for empty table cells
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// runCacheExport writes every cache file into a gzipped tarball
+func runCacheExport(cmd *cobra.Command, path string) error {
+	cacheDir, _ := cmd.Flags().GetString("cache-dir")
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+
+	count, err := scanner.ExportCache(cacheDir, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return err
+	}
+
+	fmt.Printf("✅ Exported %d cache file(s) from %s to %s\n", count, cacheDir, path)
+	return nil
+}
+
+// runCacheImport extracts a cache tarball into the cache directory
+func runCacheImport(cmd *cobra.Command, path string) error {
+	cacheDir, _ := cmd.Flags().GetString("cache-dir")
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	count, err := scanner.ImportCache(cacheDir, file)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Imported %d cache file(s) into %s\n", count, cacheDir)
+	return nil
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/graph"
+	"github.com/fullstack-spiderman/hulud-scan/internal/history"
+	"github.com/fullstack-spiderman/hulud-scan/internal/parser"
+	"github.com/fullstack-spiderman/hulud-scan/internal/report"
+	"github.com/fullstack-spiderman/hulud-scan/internal/scanner"
+	"github.com/spf13/cobra"
+)
+
+// diffCmd scans only what a change to the lockfile brings in
+var diffCmd = &cobra.Command{
+	Use:   "diff <base> [head]",
+	Short: "Compare two lockfiles and scan only the newly introduced packages",
+	Long: `Diff compares two versions of a lockfile and reports added, removed and
+upgraded packages and new dependency paths. Only package versions that head
+introduces are scanned, so a pull request fails on the findings it adds, not on
+ones that were already there.
+
+base and head are project directories or lockfile paths (any supported
+format). base may also be a git ref, in which case the head lockfile is read
+from that commit. head defaults to the current directory.
+
+Examples:
+  hulud-scan diff origin/main
+  hulud-scan diff HEAD~1 ./my-project
+  hulud-scan diff old/package-lock.json package-lock.json --format sarif`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		head := "."
+		if len(args) > 1 {
+			head = args[1]
+		}
+		if err := runDiff(cmd, args[0], head); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	// Report flags mirror the scan command
+	diffCmd.Flags().StringP("format", "f", "table", "Console output format ("+strings.Join(report.Formats(), ", ")+")")
+	diffCmd.Flags().StringArrayP("output", "o", nil, "Also write a report to a file as format=path (repeatable, e.g. --output sarif=results.sarif)")
+	diffCmd.Flags().String("template", "", "Go text/template file used by the template format")
+	diffCmd.Flags().Bool("junit-passing", false, "Include clean packages as passing testcases in JUnit output")
+	addBlocklistFlags(diffCmd)
+}
+
+// runDiff compares base and head, then scans the package versions head introduces
+func runDiff(cmd *cobra.Command, baseSpec, headSpec string) error {
+	format, _ := cmd.Flags().GetString("format")
+	out := progressWriter(format)
+	startTime := time.Now()
+
+	outputs, err := resolveOutputs(cmd)
+	if err != nil {
+		return err
+	}
+
+	headLockfile, headInfo, err := parseLockfileSpec(headSpec)
+	if err != nil {
+		return fmt.Errorf("head: %w", err)
+	}
+
+	baseLockfile, baseLabel, err := parseBaseLockfile(baseSpec, headInfo)
+	if err != nil {
+		return fmt.Errorf("base: %w", err)
+	}
+
+	baseGraph, err := graph.BuildGraph(baseLockfile)
+	if err != nil {
+		return fmt.Errorf("failed to build base graph: %w", err)
+	}
+	headGraph, err := graph.BuildGraph(headLockfile)
+	if err != nil {
+		return fmt.Errorf("failed to build head graph: %w", err)
+	}
+
+	fmt.Fprintf(out, "🔀 Comparing %s → %s\n", baseLabel, headInfo.Path)
+	changes := graph.Diff(baseGraph, headGraph)
+	printChanges(out, changes)
+
+	result := &scanner.ScanResult{Findings: make([]scanner.Finding, 0)}
+	introduced := changes.Introduced()
+	if len(introduced) > 0 {
+		fmt.Fprintf(out, "\n🔍 Scanning %d newly introduced package version%s...\n", len(introduced), plural(len(introduced), "", "s"))
+
+		sources, err := resolveBlocklistSources(cmd)
+		if err != nil {
+			return err
+		}
+		loader, err := newBlocklistLoader(cmd)
+		if err != nil {
+			return err
+		}
+		blocklist, err := loader.LoadAll(sources)
+		if err != nil {
+			return fmt.Errorf("failed to load blocklist: %w", err)
+		}
+
+		result = scanner.ScanGraphNodes(headGraph, blocklist, func(node *graph.Node) bool {
+			return changes.IsIntroduced(node.Package.Name, node.Package.Version)
+		})
+		result.TotalPackages = len(introduced)
+	}
+
+	scan := report.Scan{
+		Result:       result,
+		Lockfile:     headLockfile,
+		LockfileInfo: headInfo,
+		Graph:        headGraph,
+	}
+	meta := report.Meta{ToolVersion: Version, StartTime: startTime, EndTime: time.Now()}
+	if err := writeOutputs(outputs, []report.Scan{scan}, meta); err != nil {
+		return err
+	}
+
+	for _, finding := range result.Findings {
+		if finding.Severity == scanner.SeverityCritical {
+			fmt.Fprintln(out, "❌ Critical security issues introduced!")
+			return fmt.Errorf("critical vulnerabilities introduced by this change")
+		}
+	}
+	return nil
+}
+
+// printChanges summarizes the package and path changes
+func printChanges(w io.Writer, changes *graph.Changes) {
+	if changes.IsEmpty() {
+		fmt.Fprintln(w, "\n✅ No dependency changes")
+		return
+	}
+
+	fmt.Fprintf(w, "\n📦 Package changes: %d added, %d removed, %d upgraded\n",
+		len(changes.Added), len(changes.Removed), len(changes.Upgraded))
+	for _, ref := range changes.Added {
+		fmt.Fprintf(w, "   + %s\n", ref)
+	}
+	for _, ref := range changes.Removed {
+		fmt.Fprintf(w, "   - %s\n", ref)
+	}
+	for _, change := range changes.Upgraded {
+		arrow := "↑"
+		if change.IsDowngrade() {
+			arrow = "↓"
+		}
+		fmt.Fprintf(w, "   %s %s %s → %s\n", arrow, change.Name, change.From, change.To)
+	}
+
+	if len(changes.NewPaths) > 0 {
+		fmt.Fprintf(w, "\n🔗 New dependency paths: %d\n", len(changes.NewPaths))
+		for _, path := range changes.NewPaths {
+			fmt.Fprintf(w, "   %s\n", strings.Join(path, " → "))
+		}
+	}
+}
+
+// parseLockfileSpec parses a project directory or a lockfile path
+func parseLockfileSpec(spec string) (*parser.Lockfile, *parser.LockfileInfo, error) {
+	info, err := os.Stat(spec)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open %s: %w", spec, err)
+	}
+
+	var lockfile *parser.Lockfile
+	var lockfileInfo *parser.LockfileInfo
+	if info.IsDir() {
+		lockfile, lockfileInfo, err = parser.ParseAuto(spec)
+	} else {
+		lockfile, lockfileInfo, err = parser.ParseFile(spec)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse lockfile: %w", err)
+	}
+	return lockfile, lockfileInfo, nil
+}
+
+// parseBaseLockfile parses the base side: a path, or otherwise a git ref
+// from which the head lockfile is read
+func parseBaseLockfile(spec string, head *parser.LockfileInfo) (*parser.Lockfile, string, error) {
+	if _, err := os.Stat(spec); err == nil {
+		lockfile, info, err := parseLockfileSpec(spec)
+		if err != nil {
+			return nil, "", err
+		}
+		return lockfile, info.Path, nil
+	}
+
+	lockfile, err := history.ParseAt(filepath.Dir(head.Path), spec, head.Filename)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s is neither a file nor a git ref with %s: %w", spec, head.Filename, err)
+	}
+	return lockfile, spec + ":" + head.Path, nil
+}
@@ -0,0 +1,303 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/graph"
+	"github.com/fullstack-spiderman/hulud-scan/internal/parser"
+	"github.com/fullstack-spiderman/hulud-scan/internal/scanner"
+	"github.com/spf13/cobra"
+)
+
+// graphCmd exports the dependency graph for visualization
+var graphCmd = &cobra.Command{
+	Use:   "graph [path]",
+	Short: "Export the dependency graph as DOT, Mermaid, GraphML or JSON",
+	Long: `Graph writes the project's dependency graph: one node per installed package
+(name, version, depth, direct flag) and one edge per dependency. Blocklisted
+packages are highlighted, using the same blocklist sources as scan.
+
+--focus keeps only the packages on a chain from the project to the given
+package, which is usually what you want to look at.
+
+Examples:
+  hulud-scan graph . > deps.dot && dot -Tsvg deps.dot -o deps.svg
+  hulud-scan graph --format mermaid --focus @ctrl/tinycolor
+  hulud-scan graph --format graphml -o deps.graphml ./my-project`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := "."
+		if len(args) > 0 {
+			path = args[0]
+		}
+		if err := runGraph(cmd, path); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// graphCyclesCmd reports dependency cycles
+var graphCyclesCmd = &cobra.Command{
+	Use:   "cycles [path]",
+	Short: "List groups of packages that depend on each other in a cycle",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := "."
+		if len(args) > 0 {
+			path = args[0]
+		}
+		if err := runGraphCycles(os.Stdout, path); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// graphStatsCmd ranks packages by how much of the tree they control
+var graphStatsCmd = &cobra.Command{
+	Use:   "stats [path]",
+	Short: "Show blast-radius metrics per package (dependents, entry points, dominators, fan-out)",
+	Long: `Stats ranks packages by how much of the dependency tree they control:
+
+  DOMINATES    packages only reachable through this one; a compromise here
+               reaches all of them, whatever else is installed
+  DEPENDENTS   packages that depend on it, directly or transitively
+  ENTRY        direct dependencies of the project it is reachable from
+  DOM DEPTH    packages every chain to it must pass through
+  FAN-OUT      packages it depends on directly
+
+Examples:
+  hulud-scan graph stats
+  hulud-scan graph stats --top 0 --format json > stats.json`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := "."
+		if len(args) > 0 {
+			path = args[0]
+		}
+		if err := runGraphStats(cmd, os.Stdout, path); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(graphCmd)
+	graphCmd.AddCommand(graphCyclesCmd, graphStatsCmd)
+
+	graphStatsCmd.Flags().Int("top", 20, "Number of packages shown (0 for all)")
+	graphStatsCmd.Flags().StringP("format", "f", "table", "Output format (table, json)")
+
+	graphCmd.Flags().StringP("format", "f", graph.ExportDOT, "Output format ("+strings.Join(graph.ExportFormats(), ", ")+")")
+	graphCmd.Flags().StringP("output", "o", "", "Write to a file instead of stdout")
+	graphCmd.Flags().String("focus", "", "Only export the chains from the project to this package[@version]")
+	graphCmd.Flags().Bool("no-blocklist", false, "Do not load blocklists (nothing is highlighted)")
+	addBlocklistFlags(graphCmd)
+}
+
+// runGraph builds the dependency graph and writes it in the requested format
+func runGraph(cmd *cobra.Command, projectPath string) error {
+	format, _ := cmd.Flags().GetString("format")
+	focus, _ := cmd.Flags().GetString("focus")
+	outputPath, _ := cmd.Flags().GetString("output")
+
+	dependencyGraph, err := loadGraph(projectPath)
+	if err != nil {
+		return err
+	}
+
+	var opts graph.ExportOptions
+	if focus != "" {
+		name, version := splitPackageSpec(focus)
+		opts.Focus = dependencyGraph.FindNodes(name, version)
+		if len(opts.Focus) == 0 {
+			return fmt.Errorf("%s is not installed in %s", focus, projectPath)
+		}
+	}
+
+	if noBlocklist, _ := cmd.Flags().GetBool("no-blocklist"); !noBlocklist {
+		blocklist, err := loadGraphBlocklist(cmd)
+		if err != nil {
+			// Highlighting is a nicety; the graph itself is still worth having
+			fmt.Fprintf(os.Stderr, "⚠️  Blocklisted packages are not highlighted: %v\n", err)
+		} else {
+			opts.Highlight = func(node *graph.Node) bool {
+				return blocklist.IsBlocked(node.Package.Name, node.Package.Version) != nil
+			}
+		}
+	}
+
+	if outputPath == "" {
+		return graph.Export(os.Stdout, dependencyGraph, format, opts)
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", outputPath, err)
+	}
+
+	if err := graph.Export(file, dependencyGraph, format, opts); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	// A failed close can mean the graph never fully reached the disk
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", outputPath, err)
+	}
+	return nil
+}
+
+// runGraphCycles prints every strongly connected group of packages with an example cycle
+func runGraphCycles(w io.Writer, projectPath string) error {
+	dependencyGraph, err := loadGraph(projectPath)
+	if err != nil {
+		return err
+	}
+
+	cycles := dependencyGraph.Cycles()
+	if len(cycles) == 0 {
+		fmt.Fprintln(w, "✅ No dependency cycles")
+		return nil
+	}
+
+	fmt.Fprintf(w, "🔁 %d dependency cycle%s\n", len(cycles), plural(len(cycles), "", "s"))
+	for i, cycle := range cycles {
+		labels := make([]string, 0, len(cycle))
+		for _, node := range cycle {
+			labels = append(labels, nodeLabel(node))
+		}
+		chain := make([]string, 0, len(cycle)+1)
+		for _, node := range graph.ShortestCycle(cycle) {
+			chain = append(chain, node.Package.Name)
+		}
+
+		fmt.Fprintf(w, "\n%d. %d package%s: %s\n", i+1, len(cycle), plural(len(cycle), "", "s"), strings.Join(labels, ", "))
+		fmt.Fprintf(w, "   Cycle: %s\n", strings.Join(chain, " → "))
+	}
+	return nil
+}
+
+// packageStats is one row of graph stats
+type packageStats struct {
+	Package        string   `json:"package"`
+	Version        string   `json:"version"`
+	Path           string   `json:"path"`
+	Depth          int      `json:"depth"`
+	Direct         bool     `json:"direct"`
+	Reachable      bool     `json:"reachable"`
+	Dependents     int      `json:"dependents"`
+	EntryPoints    int      `json:"entry_points"`
+	FanOut         int      `json:"fan_out"`
+	DominatorDepth int      `json:"dominator_depth"`
+	Dominators     []string `json:"dominators"`
+	Dominates      int      `json:"dominates"`
+}
+
+// runGraphStats prints per-package metrics, the packages controlling most of the tree first
+func runGraphStats(cmd *cobra.Command, w io.Writer, projectPath string) error {
+	top, _ := cmd.Flags().GetInt("top")
+	format, _ := cmd.Flags().GetString("format")
+	if format != "table" && format != "json" {
+		return fmt.Errorf("unknown format %q (supported: table, json)", format)
+	}
+
+	dependencyGraph, err := loadGraph(projectPath)
+	if err != nil {
+		return err
+	}
+
+	metrics := dependencyGraph.Metrics()
+	rows := make([]packageStats, 0, len(dependencyGraph.Nodes))
+	for path, node := range dependencyGraph.Nodes {
+		m := metrics[node]
+		dominators := make([]string, 0, len(m.Dominators))
+		for _, dominator := range m.Dominators {
+			dominators = append(dominators, dominator.Package.Name)
+		}
+		rows = append(rows, packageStats{
+			Package:        node.Package.Name,
+			Version:        node.Package.Version,
+			Path:           path,
+			Depth:          node.Depth,
+			Direct:         node.IsDirect,
+			Reachable:      m.Reachable,
+			Dependents:     m.Dependents,
+			EntryPoints:    m.EntryPoints,
+			FanOut:         m.FanOut,
+			DominatorDepth: m.DominatorDepth,
+			Dominators:     dominators,
+			Dominates:      m.Dominates,
+		})
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Dominates != rows[j].Dominates {
+			return rows[i].Dominates > rows[j].Dominates
+		}
+		if rows[i].Dependents != rows[j].Dependents {
+			return rows[i].Dependents > rows[j].Dependents
+		}
+		return rows[i].Path < rows[j].Path
+	})
+	if top > 0 && len(rows) > top {
+		rows = rows[:top]
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(rows)
+	}
+
+	cycles := len(dependencyGraph.Cycles())
+	fmt.Fprintf(w, "📊 %d package%s, %d direct, %d dependency cycle%s\n\n",
+		len(dependencyGraph.Nodes), plural(len(dependencyGraph.Nodes), "", "s"),
+		len(dependencyGraph.Root.Dependencies), cycles, plural(cycles, "", "s"))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PACKAGE\tDOMINATES\tDEPENDENTS\tENTRY\tDOM DEPTH\tFAN-OUT\tVIA")
+	for _, row := range rows {
+		via := strings.Join(row.Dominators, " → ")
+		if !row.Reachable {
+			via = "(unreachable)"
+		}
+		fmt.Fprintf(tw, "%s@%s\t%d\t%d\t%d\t%d\t%d\t%s\n", row.Package, row.Version,
+			row.Dominates, row.Dependents, row.EntryPoints, row.DominatorDepth, row.FanOut, orDash(via))
+	}
+	return tw.Flush()
+}
+
+// loadGraph parses the lockfile in projectPath and builds its dependency graph
+func loadGraph(projectPath string) (*graph.Graph, error) {
+	lockfile, _, err := parser.ParseAuto(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse lockfile: %w", err)
+	}
+	dependencyGraph, err := graph.BuildGraph(lockfile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build graph: %w", err)
+	}
+	return dependencyGraph, nil
+}
+
+// loadGraphBlocklist loads the blocklist sources selected by the flags
+func loadGraphBlocklist(cmd *cobra.Command) (*scanner.Blocklist, error) {
+	sources, err := resolveBlocklistSources(cmd)
+	if err != nil {
+		return nil, err
+	}
+	loader, err := newBlocklistLoader(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return loader.LoadAll(sources)
+}
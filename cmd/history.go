@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/history"
+	"github.com/fullstack-spiderman/hulud-scan/internal/parser"
+	"github.com/fullstack-spiderman/hulud-scan/internal/scanner"
+	"github.com/spf13/cobra"
+)
+
+// historyCmd scans every committed version of the lockfiles
+var historyCmd = &cobra.Command{
+	Use:   "history [path]",
+	Short: "Find blocklisted packages in any committed version of the lockfiles",
+	Long: `History walks the git history of each lockfile in the project, scans every
+distinct version against the blocklist and reports which commits and date
+ranges installed a blocklisted package version. Use it after a new IOC list
+comes out to check whether a compromised version was ever installed.
+
+--since takes anything git log accepts ("2025-09-01", "3 months ago"); the
+version that was current at that date is included.
+
+Examples:
+  hulud-scan history
+  hulud-scan history ./my-project --since 2025-09-01
+  hulud-scan history --format json > exposure.json`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := "."
+		if len(args) > 0 {
+			path = args[0]
+		}
+		if err := runHistory(cmd, os.Stdout, path); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().String("since", "", "Only scan history from this date on (anything git log --since accepts)")
+	historyCmd.Flags().StringP("format", "f", "table", "Output format (table, json)")
+	addBlocklistFlags(historyCmd)
+}
+
+// lockfileHistory is the result for one lockfile
+type lockfileHistory struct {
+	Lockfile  string             `json:"lockfile"`
+	Commits   int                `json:"commits"`
+	Scanned   int                `json:"distinct_versions"`
+	Exposures []historyExposure  `json:"exposures"`
+	exposures []history.Exposure // For the table output
+}
+
+// historyExposure is the JSON form of a history.Exposure
+type historyExposure struct {
+	Package  string          `json:"package"`
+	Version  string          `json:"version"`
+	Severity string          `json:"severity"`
+	Reason   string          `json:"reason"`
+	Windows  []historyWindow `json:"windows"`
+}
+
+// historyWindow is the JSON form of a history.Window
+type historyWindow struct {
+	FirstCommit string     `json:"first_commit"`
+	FirstDate   time.Time  `json:"first_date"`
+	LastCommit  string     `json:"last_commit"`
+	LastDate    time.Time  `json:"last_date"`
+	RemovedBy   string     `json:"removed_by,omitempty"`
+	RemovedAt   *time.Time `json:"removed_at,omitempty"`
+	Commits     int        `json:"commits"`
+}
+
+// runHistory scans the history of every lockfile in projectPath
+func runHistory(cmd *cobra.Command, w io.Writer, projectPath string) error {
+	since, _ := cmd.Flags().GetString("since")
+	format, _ := cmd.Flags().GetString("format")
+	if format != "table" && format != "json" {
+		return fmt.Errorf("unknown format %q (supported: table, json)", format)
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("history needs the git command: %w", err)
+	}
+
+	type lockfileLog struct {
+		filename  string
+		revisions []history.Revision
+	}
+	logs := make([]lockfileLog, 0)
+	for _, filename := range parser.LockfileNames() {
+		revisions, err := history.Log(projectPath, filename, since)
+		if err != nil {
+			return err
+		}
+		if len(revisions) > 0 {
+			logs = append(logs, lockfileLog{filename: filename, revisions: revisions})
+		}
+	}
+	if len(logs) == 0 {
+		return fmt.Errorf("no committed lockfile found in %s", projectPath)
+	}
+
+	sources, err := resolveBlocklistSources(cmd)
+	if err != nil {
+		return err
+	}
+	loader, err := newBlocklistLoader(cmd)
+	if err != nil {
+		return err
+	}
+	blocklist, err := loader.LoadAll(sources)
+	if err != nil {
+		return fmt.Errorf("failed to load blocklist: %w", err)
+	}
+
+	results := make([]lockfileHistory, 0, len(logs))
+	critical := false
+	for _, log := range logs {
+		exposures, scanned, err := history.Scan(projectPath, log.filename, log.revisions, blocklist)
+		if err != nil {
+			return fmt.Errorf("%s: %w", log.filename, err)
+		}
+
+		result := lockfileHistory{
+			Lockfile:  filepath.Join(projectPath, log.filename),
+			Commits:   len(log.revisions),
+			Scanned:   scanned,
+			Exposures: make([]historyExposure, 0, len(exposures)),
+			exposures: exposures,
+		}
+		for _, exposure := range exposures {
+			critical = critical || exposure.Finding.Severity == scanner.SeverityCritical
+			result.Exposures = append(result.Exposures, newHistoryExposure(exposure))
+		}
+		results = append(results, result)
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		printHistory(w, results, since)
+	}
+
+	if critical {
+		return fmt.Errorf("critical blocklisted packages were installed in the lockfile history")
+	}
+	return nil
+}
+
+// newHistoryExposure converts an exposure to its JSON form
+func newHistoryExposure(exposure history.Exposure) historyExposure {
+	out := historyExposure{
+		Package:  exposure.Finding.PackageName,
+		Version:  exposure.Finding.Version,
+		Severity: string(exposure.Finding.Severity),
+		Reason:   exposure.Finding.Reason,
+		Windows:  make([]historyWindow, 0, len(exposure.Windows)),
+	}
+	for _, window := range exposure.Windows {
+		w := historyWindow{
+			FirstCommit: window.From.Commit,
+			FirstDate:   window.From.Date,
+			LastCommit:  window.To.Commit,
+			LastDate:    window.To.Date,
+			Commits:     window.Revisions,
+		}
+		if window.RemovedBy != nil {
+			w.RemovedBy = window.RemovedBy.Commit
+			w.RemovedAt = &window.RemovedBy.Date
+		}
+		out.Windows = append(out.Windows, w)
+	}
+	return out
+}
+
+// printHistory prints the exposure windows of each lockfile
+func printHistory(w io.Writer, results []lockfileHistory, since string) {
+	for i, result := range results {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		window := ""
+		if since != "" {
+			window = " since " + since
+		}
+		fmt.Fprintf(w, "📜 %s: %d commit%s%s, %d distinct version%s scanned\n", result.Lockfile,
+			result.Commits, plural(result.Commits, "", "s"), window, result.Scanned, plural(result.Scanned, "", "s"))
+
+		if len(result.exposures) == 0 {
+			fmt.Fprintln(w, "✅ No blocklisted package was ever installed")
+			continue
+		}
+
+		for _, exposure := range result.exposures {
+			finding := exposure.Finding
+			fmt.Fprintf(w, "\n🚨 %s@%s [%s] %s\n", finding.PackageName, finding.Version,
+				strings.ToUpper(string(finding.Severity)), finding.Reason)
+			for _, window := range exposure.Windows {
+				fmt.Fprintf(w, "   %s %s %q", window.From.Date.Format("2006-01-02"), window.From.Short(), window.From.Subject)
+				if window.RemovedBy != nil {
+					fmt.Fprintf(w, " → removed %s by %s %q", window.RemovedBy.Date.Format("2006-01-02"),
+						window.RemovedBy.Short(), window.RemovedBy.Subject)
+				} else {
+					fmt.Fprint(w, " → still installed")
+				}
+				fmt.Fprintf(w, " (%d commit%s)\n", window.Revisions, plural(window.Revisions, "", "s"))
+			}
+		}
+	}
+}
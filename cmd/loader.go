@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/config"
+	"github.com/fullstack-spiderman/hulud-scan/internal/scanner"
+	"github.com/fullstack-spiderman/hulud-scan/internal/signature"
+	"github.com/spf13/cobra"
+)
+
+// defaultCacheDir is where downloaded blocklists are cached unless --cache-dir says otherwise
+func defaultCacheDir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".hulud-scan", "cache")
+}
+
+// loadConfig reads the --config file, or returns an empty config when there is none
+func loadConfig(cmd *cobra.Command) (*config.Config, error) {
+	configPath, _ := cmd.Flags().GetString("config")
+	if configPath == "" {
+		return &config.Config{}, nil
+	}
+	return config.Load(configPath)
+}
+
+// resolveBlocklistSources decides which blocklists to load
+// Explicit --blocklist flags win over the config file, which wins over the default list.
+// A location given on the command line that the config file also lists keeps
+// its configured settings (auth, TTL, severity, tags).
+func resolveBlocklistSources(cmd *cobra.Command) ([]scanner.Source, error) {
+	locations, _ := cmd.Flags().GetStringArray("blocklist")
+
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if !cmd.Flags().Changed("blocklist") && len(cfg.Blocklists) > 0 {
+		return cfg.Blocklists, nil
+	}
+
+	sources := make([]scanner.Source, 0, len(locations))
+	for _, location := range locations {
+		sources = append(sources, configuredSource(cfg, location))
+	}
+	return sources, nil
+}
+
+// configuredSource returns the settings the config file has for a location
+// Locations it does not list load with none, except the built-in default list.
+func configuredSource(cfg *config.Config, location string) scanner.Source {
+	for _, source := range cfg.Blocklists {
+		if source.Location == location {
+			return source
+		}
+	}
+	if location == defaultBlocklistURL {
+		return defaultBlocklistSource
+	}
+	return scanner.Source{Location: location}
+}
+
+// addBlocklistFlags adds the flags that choose and load blocklists to a command
+// They are persistent so subcommands (blocklist show, ...) share them.
+func addBlocklistFlags(cmd *cobra.Command) {
+	flags := cmd.PersistentFlags()
+	flags.StringP("config", "c", "", "Path to config file")
+	flags.StringArray("blocklist", []string{defaultBlocklistURL},
+		"Blocklist URL (http(s)://, git+…#path@ref, oci://) or local file path (repeat to merge several lists)")
+	flags.String("cache-dir", defaultCacheDir(), "Cache directory for downloaded blocklists")
+	flags.Duration("cache-ttl", time.Hour, "How long a cached blocklist is used before asking the server for changes")
+	flags.Bool("no-cache", false, "Disable caching (always download fresh)")
+	flags.Bool("offline", false, "Never use the network: only local files and cached blocklists (of any age)")
+	flags.StringArray("trusted-key", nil, "Public key (or key file) trusted to sign blocklists (repeatable)")
+	flags.Bool("require-signed-blocklist", false, "Refuse blocklists without a valid signature from a trusted key")
+}
+
+// newBlocklistLoader builds a blocklist loader from the cache and signature flags
+// Trusted keys from the flags and the config file are combined; signatures are
+// required if either asks for them.
+func newBlocklistLoader(cmd *cobra.Command) (*scanner.Loader, error) {
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheDir, _ := cmd.Flags().GetString("cache-dir")
+	if noCache, _ := cmd.Flags().GetBool("no-cache"); noCache {
+		cacheDir = "" // Disable caching
+	}
+
+	ttl, _ := cmd.Flags().GetDuration("cache-ttl")
+
+	keyValues, _ := cmd.Flags().GetStringArray("trusted-key")
+	keys, err := parseTrustedKeys(append(keyValues, cfg.TrustedKeys...))
+	if err != nil {
+		return nil, err
+	}
+
+	requireSigned, _ := cmd.Flags().GetBool("require-signed-blocklist")
+	offline, _ := cmd.Flags().GetBool("offline")
+
+	return &scanner.Loader{
+		CacheDir:      cacheDir,
+		TTL:           ttl,
+		TrustedKeys:   keys,
+		RequireSigned: requireSigned || cfg.RequireSignedBlocklist,
+		Offline:       offline,
+		HTTP:          cfg.HTTP,
+	}, nil
+}
+
+// parseTrustedKeys parses public keys given inline or as paths to key files
+func parseTrustedKeys(values []string) ([]signature.PublicKey, error) {
+	keys := make([]signature.PublicKey, 0, len(values))
+	for _, value := range values {
+		text := value
+		if data, err := os.ReadFile(value); err == nil {
+			text = string(data)
+		}
+
+		key, err := signature.ParseKey(text)
+		if err != nil {
+			return nil, fmt.Errorf("trusted key %q: %w", value, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/report"
+	"github.com/spf13/cobra"
+)
+
+// output is one destination for a rendered report
+type output struct {
+	format   string          // Format name (e.g. "sarif")
+	path     string          // File to write, or "" to use writer
+	writer   io.Writer       // Stream to write when path is empty
+	reporter report.Reporter // Renderer for the format
+}
+
+// resolveOutputs builds the console output from --format plus one output per --output flag
+func resolveOutputs(cmd *cobra.Command) ([]output, error) {
+	format, _ := cmd.Flags().GetString("format")
+	specs, _ := cmd.Flags().GetStringArray("output")
+	includePassing, _ := cmd.Flags().GetBool("junit-passing")
+	templatePath, _ := cmd.Flags().GetString("template")
+
+	opts := report.Options{
+		IncludePassing: includePassing,
+		TemplatePath:   templatePath,
+		Workspace:      os.Getenv("GITHUB_WORKSPACE"),
+	}
+
+	console, err := report.New(format, opts)
+	if err != nil {
+		return nil, err
+	}
+	outputs := []output{{format: format, writer: os.Stdout, reporter: console}}
+
+	for _, spec := range specs {
+		specFormat, path, err := parseOutputSpec(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		reporter, err := report.New(specFormat, opts)
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, output{format: specFormat, path: path, reporter: reporter})
+	}
+
+	// Inside GitHub Actions, also annotate findings inline on the pull request
+	if os.Getenv("GITHUB_ACTIONS") == "true" && !hasFormat(outputs, "github") {
+		annotations, err := report.New("github", opts)
+		if err != nil {
+			return nil, err
+		}
+		// The runner reads workflow commands from both streams; stay off stdout if a report owns it
+		outputs = append(outputs, output{format: "github", writer: progressWriter(format), reporter: annotations})
+	}
+
+	return outputs, nil
+}
+
+// hasFormat reports whether any output already renders the given format
+func hasFormat(outputs []output, format string) bool {
+	for _, out := range outputs {
+		if strings.EqualFold(out.format, format) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseOutputSpec splits an --output value of the form format=path
+func parseOutputSpec(spec string) (format string, path string, err error) {
+	format, path, found := strings.Cut(spec, "=")
+	format = strings.TrimSpace(format)
+	path = strings.TrimSpace(path)
+
+	if !found || format == "" || path == "" {
+		return "", "", fmt.Errorf("invalid --output %q: expected format=path (e.g. sarif=results.sarif)", spec)
+	}
+	return format, path, nil
+}
+
+// writeOutputs renders the scans once per output
+func writeOutputs(outputs []output, scans []report.Scan, meta report.Meta) error {
+	for _, out := range outputs {
+		if out.path == "" {
+			if err := out.reporter.Report(out.writer, scans, meta); err != nil {
+				return fmt.Errorf("failed to write %s report: %w", out.format, err)
+			}
+			continue
+		}
+
+		if err := writeOutputFile(out, scans, meta); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeOutputFile renders one report into a file
+func writeOutputFile(out output, scans []report.Scan, meta report.Meta) error {
+	file, err := os.Create(out.path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s report: %w", out.format, err)
+	}
+
+	if err := out.reporter.Report(file, scans, meta); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write %s report to %s: %w", out.format, out.path, err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close %s report: %w", out.format, err)
+	}
+	return nil
+}
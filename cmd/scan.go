@@ -2,23 +2,40 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/fullstack-spiderman/hulud-scan/internal/graph"
 	"github.com/fullstack-spiderman/hulud-scan/internal/parser"
+	"github.com/fullstack-spiderman/hulud-scan/internal/report"
 	"github.com/fullstack-spiderman/hulud-scan/internal/scanner"
 	"github.com/spf13/cobra"
 )
 
+// defaultBlocklistURL is the Wiz Shai-Hulud 2.0 IOC list
+const defaultBlocklistURL = "https://github.com/wiz-sec-public/wiz-research-iocs/blob/main/reports/shai-hulud-2-packages.csv"
+
+// defaultBlocklistSource adds the context the Wiz list itself does not carry
+var defaultBlocklistSource = scanner.Source{
+	Location: defaultBlocklistURL,
+	Reason:   "Compromised package (Shai-Hulud attack)",
+	Tags:     []string{"shai-hulud-2"},
+}
+
 // scanCmd represents the scan command
 var scanCmd = &cobra.Command{
 	Use:   "scan [path]",
 	Short: "Scan a project for compromised dependencies",
 	Long: `Scan automatically detects and analyzes the lockfile (package-lock.json,
 yarn.lock, pnpm-lock.yaml, or bun.lockb) in the specified directory and checks
-for known compromised packages and suspicious lifecycle scripts.`,
+for known compromised packages and suspicious lifecycle scripts.
+
+It also compares the lockfile with package.json and reports drift that points
+at a hand-edited or tampered lockfile: declared dependencies the lockfile does
+not install, root entries package.json does not declare, and resolved versions
+outside the declared range.`,
 	Args: cobra.MaximumNArgs(1), // Accept 0 or 1 arguments
 	Run: func(cmd *cobra.Command, args []string) {
 		// This function runs when the command is executed
@@ -27,7 +44,8 @@ for known compromised packages and suspicious lifecycle scripts.`,
 			path = args[0]
 		}
 
-		fmt.Printf("🔍 Scanning project at: %s\n", path)
+		format, _ := cmd.Flags().GetString("format")
+		fmt.Fprintf(progressWriter(format), "🔍 Scanning project at: %s\n", path)
 
 		// Run the scan
 		if err := runScan(path, cmd); err != nil {
@@ -42,103 +60,108 @@ func init() {
 	rootCmd.AddCommand(scanCmd)
 
 	// Add flags specific to the scan command
-	// --format flag for output format (table or json)
-	scanCmd.Flags().StringP("format", "f", "table", "Output format (table or json)")
+	// --format flag for the console output format
+	scanCmd.Flags().StringP("format", "f", "table", "Console output format ("+strings.Join(report.Formats(), ", ")+")")
+
+	// --output flag for additional report files (repeatable)
+	scanCmd.Flags().StringArrayP("output", "o", nil, "Also write a report to a file as format=path (repeatable, e.g. --output sarif=results.sarif)")
+
+	// --template flag for the template output format
+	scanCmd.Flags().String("template", "", "Go text/template file used by the template format")
 
-	// --config flag for custom config file
-	scanCmd.Flags().StringP("config", "c", "", "Path to config file")
+	// --junit-passing flag to list clean packages as passing testcases
+	scanCmd.Flags().Bool("junit-passing", false, "Include clean packages as passing testcases in JUnit output")
 
-	// --blocklist flag for blocklist URL or local path
-	scanCmd.Flags().String("blocklist",
-		"https://github.com/wiz-sec-public/wiz-research-iocs/blob/main/reports/shai-hulud-2-packages.csv",
-		"Blocklist URL or local file path")
+	// --no-drift flag to skip the lockfile/package.json comparison
+	scanCmd.Flags().Bool("no-drift", false, "Do not check the lockfile against package.json")
 
-	// --cache-dir flag for cache directory
-	homeDir, _ := os.UserHomeDir()
-	defaultCacheDir := filepath.Join(homeDir, ".hulud-scan", "cache")
-	scanCmd.Flags().String("cache-dir", defaultCacheDir, "Cache directory for downloaded blocklists")
+	// --config, --blocklist, cache and signature flags
+	addBlocklistFlags(scanCmd)
+}
 
-	// --no-cache flag to disable caching
-	scanCmd.Flags().Bool("no-cache", false, "Disable caching (always download fresh)")
+// progressWriter returns where status messages should be written
+// Machine-readable formats own stdout, so progress goes to stderr for them
+func progressWriter(format string) io.Writer {
+	if report.IsMachineReadable(format) {
+		return os.Stderr
+	}
+	return os.Stdout
 }
 
 // runScan performs the actual scanning logic
 func runScan(projectPath string, cmd *cobra.Command) error {
+	format, _ := cmd.Flags().GetString("format")
+	out := progressWriter(format)
+	startTime := time.Now()
+
+	// Resolve every requested output up front so bad flags fail before any work
+	outputs, err := resolveOutputs(cmd)
+	if err != nil {
+		return err
+	}
+
 	// Auto-detect and parse lockfile
-	fmt.Printf("🔎 Detecting lockfile in: %s\n", projectPath)
+	fmt.Fprintf(out, "🔎 Detecting lockfile in: %s\n", projectPath)
 
 	lockfile, lockfileInfo, err := parser.ParseAuto(projectPath)
 	if err != nil {
 		return fmt.Errorf("failed to parse lockfile: %w", err)
 	}
 
-	fmt.Printf("📄 Detected: %s\n", lockfileInfo.Type.String())
-	fmt.Printf("✅ Found %d packages\n", len(lockfile.Packages))
-	fmt.Printf("Project: %s@%s\n", lockfile.Name, lockfile.Version)
+	fmt.Fprintf(out, "📄 Detected: %s\n", lockfileInfo.Type.String())
+	fmt.Fprintf(out, "✅ Found %d packages\n", len(lockfile.Packages))
+	fmt.Fprintf(out, "Project: %s@%s\n", lockfile.Name, lockfile.Version)
 
 	// Step 2: Build dependency graph
-	fmt.Println("\n📊 Building dependency graph...")
+	fmt.Fprintln(out, "\n📊 Building dependency graph...")
 	dependencyGraph, err := graph.BuildGraph(lockfile)
 	if err != nil {
 		return fmt.Errorf("failed to build graph: %w", err)
 	}
 
-	// Step 3: Load or download blocklist
-	blocklistPath, _ := cmd.Flags().GetString("blocklist")
-	cacheDir, _ := cmd.Flags().GetString("cache-dir")
-	noCache, _ := cmd.Flags().GetBool("no-cache")
+	// Step 3: Load or download blocklists
+	sources, err := resolveBlocklistSources(cmd)
+	if err != nil {
+		return err
+	}
 
-	if noCache {
-		cacheDir = "" // Disable caching
+	loader, err := newBlocklistLoader(cmd)
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf("📋 Loading blocklist from: %s\n", blocklistPath)
-	blocklist, err := scanner.LoadOrDownloadBlocklist(blocklistPath, cacheDir)
+	for _, source := range sources {
+		fmt.Fprintf(out, "📋 Loading blocklist from: %s\n", source.Location)
+	}
+	blocklist, err := loader.LoadAll(sources)
 	if err != nil {
 		return fmt.Errorf("failed to load blocklist: %w", err)
 	}
-	fmt.Printf("✅ Loaded %d blocklist entries\n", len(blocklist.Entries))
+	fmt.Fprintf(out, "✅ Loaded %d blocklist entries from %d source(s)\n", len(blocklist.Entries), len(sources))
 
 	// Step 4: Scan for compromised packages
-	fmt.Println("\n🔍 Scanning for compromised packages...")
+	fmt.Fprintln(out, "\n🔍 Scanning for compromised packages...")
 	result := scanner.ScanGraph(dependencyGraph, blocklist)
 
-	// Step 5: Display results
-	fmt.Println()
-	fmt.Println(strings.Repeat("=", 60))
-	fmt.Println("SCAN RESULTS")
-	fmt.Println(strings.Repeat("=", 60))
-	fmt.Println()
-
-	fmt.Printf("Total packages scanned: %d\n", result.TotalPackages)
-	fmt.Printf("Issues found: %d\n\n", result.IssuesFound)
-
-	if result.IssuesFound == 0 {
-		fmt.Println("✅ No compromised packages detected!")
-		return nil
+	// Step 5: Check the lockfile against package.json
+	if noDrift, _ := cmd.Flags().GetBool("no-drift"); !noDrift && lockfile.Manifest != nil {
+		fmt.Fprintln(out, "🔍 Checking lockfile against package.json...")
+		drift := scanner.CheckDrift(lockfile, dependencyGraph)
+		result.Findings = append(result.Findings, drift...)
+		result.IssuesFound += len(drift)
 	}
 
-	// Display findings
-	fmt.Printf("⚠️  SECURITY ISSUES DETECTED:\n\n")
-
-	for i, finding := range result.Findings {
-		fmt.Printf("%d. %s@%s [%s]\n", i+1, finding.PackageName, finding.Version, strings.ToUpper(string(finding.Severity)))
-
-		// Show dependency path
-		pathStr := strings.Join(finding.Path, " → ")
-		dependencyType := "transitive"
-		if finding.IsDirect {
-			dependencyType = "direct"
-		}
-		fmt.Printf("   Type: %s dependency\n", dependencyType)
-		fmt.Printf("   Path: %s\n", pathStr)
-		fmt.Printf("   Reason: %s\n", finding.Reason)
-
-		if finding.CVE != "" {
-			fmt.Printf("   CVE: %s\n", finding.CVE)
-		}
+	// Step 6: Display results
+	scan := report.Scan{
+		Result:       result,
+		Lockfile:     lockfile,
+		LockfileInfo: lockfileInfo,
+		Graph:        dependencyGraph,
+	}
 
-		fmt.Println()
+	meta := report.Meta{ToolVersion: Version, StartTime: startTime, EndTime: time.Now()}
+	if err := writeOutputs(outputs, []report.Scan{scan}, meta); err != nil {
+		return err
 	}
 
 	// Exit with error code if critical issues found
@@ -151,7 +174,7 @@ func runScan(projectPath string, cmd *cobra.Command) error {
 	}
 
 	if hasCritical {
-		fmt.Println("❌ Critical security issues detected!")
+		fmt.Fprintln(out, "❌ Critical security issues detected!")
 		return fmt.Errorf("critical vulnerabilities found in dependencies")
 	}
 
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/graph"
+	"github.com/spf13/cobra"
+)
+
+// whyCmd explains how a package ends up in the dependency tree
+var whyCmd = &cobra.Command{
+	Use:   "why <package[@version]> [path]",
+	Short: "Show every dependency chain that pulls in a package",
+	Long: `Why prints each installed copy of a package with every chain of
+dependencies that leads to it from the project, merged into a tree. The package
+does not need to be blocklisted. In npm workspaces, chains that start at a
+workspace package are shown as well, in a tree of their own.
+
+Examples:
+  hulud-scan why lodash
+  hulud-scan why @ctrl/tinycolor@4.1.1 ./my-project
+  hulud-scan why debug --limit 5`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := "."
+		if len(args) > 1 {
+			path = args[1]
+		}
+		if err := runWhy(cmd, os.Stdout, args[0], path); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(whyCmd)
+
+	whyCmd.Flags().Int("limit", 20, "Maximum number of chains shown per installed copy (0 for all)")
+}
+
+// runWhy prints the dependency chains to every copy of a package
+func runWhy(cmd *cobra.Command, w io.Writer, spec, projectPath string) error {
+	limit, _ := cmd.Flags().GetInt("limit")
+	if limit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+
+	dependencyGraph, err := loadGraph(projectPath)
+	if err != nil {
+		return err
+	}
+
+	name, version := splitPackageSpec(spec)
+	nodes := dependencyGraph.FindNodes(name, version)
+	if len(nodes) == 0 {
+		return fmt.Errorf("%s is not installed in %s", spec, projectPath)
+	}
+
+	// Workspace packages are roots too: nothing in the lockfile depends on them
+	starts := append([]*graph.Node{dependencyGraph.Root}, dependencyGraph.Workspaces()...)
+
+	for i, node := range nodes {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		paths, truncated := whyPaths(dependencyGraph, starts, node, limit)
+		fmt.Fprintf(w, "📦 %s (%s)\n", nodeLabel(node), packagePath(dependencyGraph, node))
+		if len(paths) == 0 && isWhyStart(starts, node) {
+			fmt.Fprintln(w, "   Workspace package of the project")
+			continue
+		}
+		if len(paths) == 0 {
+			fmt.Fprintln(w, "   Not reachable from the project or its workspaces (nothing in the lockfile depends on it)")
+			continue
+		}
+
+		// One tree per start; whyPaths returns the chains grouped by where they start
+		for start := 0; start < len(paths); {
+			end := start + 1
+			for end < len(paths) && paths[end][0] == paths[start][0] {
+				end++
+			}
+			printPathTree(w, paths[start:end])
+			start = end
+		}
+		if truncated {
+			fmt.Fprintf(w, "   … more chains exist; showing the %d shortest (use --limit 0 for all)\n", len(paths))
+		} else {
+			fmt.Fprintf(w, "   %d chain%s\n", len(paths), plural(len(paths), "", "s"))
+		}
+	}
+	return nil
+}
+
+// whyPaths collects the chains to target from each start in turn, at most limit in total
+// A start that is the target itself is skipped; the package is not its own reason.
+func whyPaths(g *graph.Graph, starts []*graph.Node, target *graph.Node, limit int) ([]graph.NodePath, bool) {
+	paths := make([]graph.NodePath, 0)
+	for _, start := range starts {
+		if start == target {
+			continue
+		}
+
+		remaining := 0
+		if limit > 0 {
+			remaining = limit - len(paths)
+			if remaining == 0 {
+				// Full already; any chain from this start is one we leave out
+				more, _ := g.AllPathsFrom(start, target, 1)
+				if len(more) > 0 {
+					return paths, true
+				}
+				continue
+			}
+		}
+
+		found, truncated := g.AllPathsFrom(start, target, remaining)
+		paths = append(paths, found...)
+		if truncated {
+			return paths, true
+		}
+	}
+	return paths, false
+}
+
+// isWhyStart reports whether node is one of the chain starts
+func isWhyStart(starts []*graph.Node, node *graph.Node) bool {
+	for _, start := range starts {
+		if start == node {
+			return true
+		}
+	}
+	return false
+}
+
+// pathTree is a prefix tree of dependency chains
+type pathTree struct {
+	node     *graph.Node
+	children []*pathTree
+}
+
+// printPathTree merges chains that share a prefix and prints them as a tree
+func printPathTree(w io.Writer, paths []graph.NodePath) {
+	root := &pathTree{node: paths[0][0]}
+	for _, path := range paths {
+		current := root
+		for _, node := range path[1:] {
+			var next *pathTree
+			for _, child := range current.children {
+				if child.node == node {
+					next = child
+					break
+				}
+			}
+			if next == nil {
+				next = &pathTree{node: node}
+				current.children = append(current.children, next)
+			}
+			current = next
+		}
+	}
+
+	fmt.Fprintln(w, nodeLabel(root.node))
+	printPathChildren(w, root, "")
+}
+
+// printPathChildren prints the children of a tree node with box-drawing connectors
+func printPathChildren(w io.Writer, tree *pathTree, indent string) {
+	for i, child := range tree.children {
+		connector, nested := "├── ", "│   "
+		if i == len(tree.children)-1 {
+			connector, nested = "└── ", "    "
+		}
+		fmt.Fprintf(w, "%s%s%s\n", indent, connector, nodeLabel(child.node))
+		printPathChildren(w, child, indent+nested)
+	}
+}
+
+// nodeLabel formats a node as name@version
+func nodeLabel(node *graph.Node) string {
+	if node.Package.Version == "" {
+		return node.Package.Name
+	}
+	return node.Package.Name + "@" + node.Package.Version
+}
+
+// packagePath returns the lockfile path of a node
+func packagePath(g *graph.Graph, node *graph.Node) string {
+	for path, candidate := range g.Nodes {
+		if candidate == node {
+			return path
+		}
+	}
+	return node.Package.Name
+}
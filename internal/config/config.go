@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/scanner"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the hulud-scan configuration file
+//
+// Example (.hulud-scan.yaml):
+//
+//	blocklists:
+//	  - https://github.com/wiz-sec-public/wiz-research-iocs/blob/main/reports/shai-hulud-2-packages.csv
+//	  - url: https://security.example.com/internal-blocklist.csv
+//	    severity: high
+//	    tags: [internal]
+//	    auth:
+//	      token_env: INTERNAL_BLOCKLIST_TOKEN
+//	http:
+//	  proxy: http://proxy.corp.example:3128
+//	  ca_cert: /etc/ssl/corp-ca.pem
+//	trusted_keys:
+//	  - RWQf6LRCGA9i53mlYecO4IzT51TGPpvWucNSCh1CBM0QTaLn73Y7GFO3
+//	require_signed_blocklist: true
+type Config struct {
+	Blocklists             []scanner.Source    `yaml:"blocklists"`               // Blocklists to load and merge
+	TrustedKeys            []string            `yaml:"trusted_keys"`             // Public keys (or key files) for blocklist signatures
+	RequireSignedBlocklist bool                `yaml:"require_signed_blocklist"` // Refuse unsigned blocklists
+	HTTP                   scanner.HTTPOptions `yaml:"http"`                     // Proxy, TLS and retry settings for downloads
+}
+
+// Load reads a YAML config file
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	for i, source := range cfg.Blocklists {
+		if source.Location == "" {
+			return nil, fmt.Errorf("config file %s: blocklist #%d has no url", path, i+1)
+		}
+	}
+
+	return &cfg, nil
+}
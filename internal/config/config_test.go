@@ -0,0 +1,114 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfig writes a config file into a temp dir and returns its path
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".hulud-scan.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoad_Blocklists(t *testing.T) {
+	// Arrange - mix the scalar and mapping forms
+	path := writeConfig(t, `
+blocklists:
+  - https://example.com/wiz.csv
+  - url: ./internal.csv
+  - url: https://example.com/hourly.csv
+    ttl: 10m
+    severity: High
+    reason: Internal IOC feed
+    tags: [campaign-x]
+    auth:
+      token_env: IOC_TOKEN
+http:
+  proxy: http://proxy.example:3128
+  ca_cert: /etc/ssl/corp.pem
+  retries: 4
+`)
+
+	// Act
+	cfg, err := Load(path)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, cfg.Blocklists, 3)
+	assert.Equal(t, "https://example.com/wiz.csv", cfg.Blocklists[0].Location)
+	assert.Equal(t, "./internal.csv", cfg.Blocklists[1].Location)
+	assert.Zero(t, cfg.Blocklists[1].TTL)
+	assert.Equal(t, 10*time.Minute, cfg.Blocklists[2].TTL)
+	assert.Equal(t, scanner.SeverityHigh, cfg.Blocklists[2].Severity)
+	assert.Equal(t, "Internal IOC feed", cfg.Blocklists[2].Reason)
+	assert.Equal(t, []string{"campaign-x"}, cfg.Blocklists[2].Tags)
+	require.NotNil(t, cfg.Blocklists[2].Auth)
+	assert.Equal(t, "IOC_TOKEN", cfg.Blocklists[2].Auth.TokenEnv)
+	assert.Equal(t, "http://proxy.example:3128", cfg.HTTP.Proxy)
+	assert.Equal(t, "/etc/ssl/corp.pem", cfg.HTTP.CACert)
+	assert.Equal(t, 4, cfg.HTTP.Retries)
+}
+
+func TestLoad_Signatures(t *testing.T) {
+	path := writeConfig(t, `
+blocklists:
+  - https://example.com/signed.csv
+trusted_keys:
+  - RWQf6LRCGA9i53mlYecO4IzT51TGPpvWucNSCh1CBM0QTaLn73Y7GFO3
+require_signed_blocklist: true
+`)
+
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"RWQf6LRCGA9i53mlYecO4IzT51TGPpvWucNSCh1CBM0QTaLn73Y7GFO3"}, cfg.TrustedKeys)
+	assert.True(t, cfg.RequireSignedBlocklist)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{
+			name:    "invalid yaml",
+			content: "blocklists: [",
+			errMsg:  "failed to parse config file",
+		},
+		{
+			name:    "source without url",
+			content: "blocklists:\n  - url: \"\"\n",
+			errMsg:  "has no url",
+		},
+		{
+			name:    "unknown source severity",
+			content: "blocklists:\n  - url: ./list.csv\n    severity: severe\n",
+			errMsg:  "unknown severity \"severe\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeConfig(t, tt.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestLoad_FileNotFound(t *testing.T) {
+	_, err := Load("does-not-exist.yaml")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read config file")
+}
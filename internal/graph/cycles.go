@@ -0,0 +1,149 @@
+package graph
+
+import "sort"
+
+// StronglyConnectedComponents groups the nodes (root included) into strongly
+// connected components using Tarjan's algorithm
+// Two packages share a component when each can reach the other. Nodes in a
+// component are sorted by name and version; components are returned in
+// reverse topological order (a component only depends on earlier ones).
+func (g *Graph) StronglyConnectedComponents() [][]*Node {
+	t := &tarjan{
+		index:   make(map[*Node]int),
+		lowlink: make(map[*Node]int),
+		onStack: make(map[*Node]bool),
+	}
+
+	for _, node := range g.sortedAllNodes() {
+		if _, seen := t.index[node]; !seen {
+			t.visit(node)
+		}
+	}
+	return t.components
+}
+
+// Cycles returns the components that contain a dependency cycle: two or more
+// packages that depend on each other, or a package that depends on itself
+// Cycles are sorted by their first package name.
+func (g *Graph) Cycles() [][]*Node {
+	cycles := make([][]*Node, 0)
+	for _, component := range g.StronglyConnectedComponents() {
+		if len(component) > 1 || dependsOn(component[0], component[0]) {
+			cycles = append(cycles, component)
+		}
+	}
+
+	sort.SliceStable(cycles, func(i, j int) bool {
+		return cycles[i][0].Package.Name < cycles[j][0].Package.Name
+	})
+	return cycles
+}
+
+// tarjan holds the state of one strongly connected component search
+type tarjan struct {
+	next       int
+	index      map[*Node]int
+	lowlink    map[*Node]int
+	stack      []*Node
+	onStack    map[*Node]bool
+	components [][]*Node
+}
+
+// visit runs Tarjan's depth-first search from node
+func (t *tarjan) visit(node *Node) {
+	t.index[node] = t.next
+	t.lowlink[node] = t.next
+	t.next++
+	t.stack = append(t.stack, node)
+	t.onStack[node] = true
+
+	for _, dep := range sortedNodes(node.Dependencies) {
+		if _, seen := t.index[dep]; !seen {
+			t.visit(dep)
+			t.lowlink[node] = min(t.lowlink[node], t.lowlink[dep])
+		} else if t.onStack[dep] {
+			t.lowlink[node] = min(t.lowlink[node], t.index[dep])
+		}
+	}
+
+	// node is the first of its component to be visited: pop the component
+	if t.lowlink[node] == t.index[node] {
+		component := make([]*Node, 0)
+		for {
+			last := t.stack[len(t.stack)-1]
+			t.stack = t.stack[:len(t.stack)-1]
+			t.onStack[last] = false
+			component = append(component, last)
+			if last == node {
+				break
+			}
+		}
+		t.components = append(t.components, sortedNodes(component))
+	}
+}
+
+// sortedAllNodes returns the root followed by every package node in path order
+func (g *Graph) sortedAllNodes() []*Node {
+	paths := make([]string, 0, len(g.Nodes))
+	for path := range g.Nodes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	nodes := make([]*Node, 0, len(paths)+1)
+	if g.Root != nil {
+		nodes = append(nodes, g.Root)
+	}
+	return append(nodes, nodesByPath(g, paths)...)
+}
+
+// dependsOn reports whether from lists to as a direct dependency
+func dependsOn(from, to *Node) bool {
+	for _, dep := range from.Dependencies {
+		if dep == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ShortestCycle returns a shortest chain that leaves the first node of a
+// cycle and comes back to it, staying inside the cycle
+// It returns nil when the nodes do not form a cycle.
+func ShortestCycle(cycle []*Node) NodePath {
+	if len(cycle) == 0 {
+		return nil
+	}
+	start := cycle[0]
+	inside := make(map[*Node]bool, len(cycle))
+	for _, node := range cycle {
+		inside[node] = true
+	}
+
+	previous := make(map[*Node]*Node)
+	queue := []*Node{start}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for _, dep := range sortedNodes(node.Dependencies) {
+			if dep == start {
+				// Walk back to the start to recover the chain
+				path := NodePath{start}
+				for n := node; n != start; n = previous[n] {
+					path = append(path, n)
+				}
+				path = append(path, start)
+				for i, j := 1, len(path)-2; i < j; i, j = i+1, j-1 {
+					path[i], path[j] = path[j], path[i]
+				}
+				return path
+			}
+			if inside[dep] && previous[dep] == nil {
+				previous[dep] = node
+				queue = append(queue, dep)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// componentNames lists the package names of each component
+func componentNames(components [][]*Node) [][]string {
+	out := make([][]string, 0, len(components))
+	for _, component := range components {
+		names := make([]string, 0, len(component))
+		for _, node := range component {
+			names = append(names, node.Package.Name)
+		}
+		out = append(out, names)
+	}
+	return out
+}
+
+// buildCyclicGraph builds the graph of the cyclic npm fixture
+func buildCyclicGraph(t *testing.T) *Graph {
+	t.Helper()
+
+	lockfile, err := parser.ParseLockfile("../../testdata/npm/cyclic/package-lock.json")
+	require.NoError(t, err)
+
+	g, err := BuildGraph(lockfile)
+	require.NoError(t, err)
+	return g
+}
+
+func TestCycles(t *testing.T) {
+	tests := []struct {
+		name     string
+		edges    [][2]string
+		expected [][]string
+	}{
+		{
+			name:     "acyclic",
+			edges:    [][2]string{{"root", "a"}, {"a", "b"}, {"root", "b"}},
+			expected: [][]string{},
+		},
+		{
+			name:     "two cycles",
+			edges:    [][2]string{{"root", "a"}, {"a", "b"}, {"b", "a"}, {"b", "x"}, {"x", "z"}, {"z", "y"}, {"y", "x"}},
+			expected: [][]string{{"a", "b"}, {"x", "y", "z"}},
+		},
+		{
+			name:     "self dependency",
+			edges:    [][2]string{{"root", "a"}, {"a", "a"}},
+			expected: [][]string{{"a"}},
+		},
+		{
+			name:     "cycle the root never reaches",
+			edges:    [][2]string{{"root", "a"}, {"x", "y"}, {"y", "x"}},
+			expected: [][]string{{"x", "y"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := testGraph(tt.edges)
+
+			assert.Equal(t, tt.expected, componentNames(g.Cycles()))
+		})
+	}
+}
+
+func TestStronglyConnectedComponents_CoversEveryNode(t *testing.T) {
+	g := testGraph([][2]string{{"root", "a"}, {"a", "b"}, {"b", "a"}, {"root", "c"}})
+
+	components := g.StronglyConnectedComponents()
+
+	assert.Equal(t, [][]string{{"a", "b"}, {"c"}, {"root"}}, componentNames(components),
+		"dependencies come before their dependents")
+}
+
+func TestBuildGraph_Cyclic(t *testing.T) {
+	// Act
+	g := buildCyclicGraph(t)
+
+	// Assert - depths are shortest distances despite the cycle
+	assert.Equal(t, 1, g.Nodes["node_modules/es5-ext"].Depth)
+	assert.Equal(t, 2, g.Nodes["node_modules/es6-iterator"].Depth)
+	assert.Equal(t, 2, g.Nodes["node_modules/es6-symbol"].Depth)
+	assert.Equal(t, 3, g.Nodes["node_modules/d"].Depth)
+	assert.Equal(t, 4, g.Nodes["node_modules/type"].Depth)
+
+	assert.Equal(t, [][]string{{"d", "es5-ext", "es6-iterator", "es6-symbol"}}, componentNames(g.Cycles()))
+}
+
+func TestFindPath_Cyclic(t *testing.T) {
+	g := buildCyclicGraph(t)
+
+	path := g.FindPath("node_modules/type")
+
+	require.Len(t, path, 5, "a shortest chain, not one around the cycle")
+	assert.Equal(t, DependencyPath{"test-cyclic", "es5-ext"}, path[:2])
+	assert.Equal(t, DependencyPath{"d", "type"}, path[3:])
+}
+
+func TestAllPaths_Cyclic(t *testing.T) {
+	g := buildCyclicGraph(t)
+
+	paths, truncated := g.AllPaths(g.Nodes["node_modules/d"], 0)
+
+	assert.False(t, truncated)
+	assert.Equal(t, []string{
+		"test-cyclic > es5-ext > es6-iterator > d",
+		"test-cyclic > es5-ext > es6-symbol > d",
+		"test-cyclic > es5-ext > es6-iterator > es6-symbol > d",
+	}, pathNames(paths))
+	for _, path := range paths {
+		seen := make(map[*Node]bool)
+		for _, node := range path {
+			assert.False(t, seen[node], "a chain never repeats a package")
+			seen[node] = true
+		}
+	}
+}
+
+// pathNames formats each chain with names
+func pathNames(paths []NodePath) []string {
+	out := make([]string, 0, len(paths))
+	for _, path := range paths {
+		out = append(out, names(path))
+	}
+	return out
+}
+
+func TestShortestCycle(t *testing.T) {
+	g := testGraph([][2]string{{"root", "a"}, {"a", "b"}, {"b", "c"}, {"c", "a"}, {"b", "a"}, {"s", "s"}})
+	cycles := g.Cycles()
+	require.Len(t, cycles, 2)
+
+	assert.Equal(t, "a > b > a", names(ShortestCycle(cycles[0])))
+	assert.Equal(t, "s > s", names(ShortestCycle(cycles[1])))
+	assert.Nil(t, ShortestCycle([]*Node{g.Root}), "the root is not in a cycle")
+}
@@ -0,0 +1,195 @@
+package graph
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/semver"
+)
+
+// PackageRef identifies one installed package version
+type PackageRef struct {
+	Name    string
+	Version string
+}
+
+// String formats the reference as name@version
+func (r PackageRef) String() string {
+	return r.Name + "@" + r.Version
+}
+
+// VersionChange is a package installed at a single version on both sides,
+// but a different one
+type VersionChange struct {
+	Name string
+	From string
+	To   string
+}
+
+// IsDowngrade reports whether the new version is older than the old one
+func (c VersionChange) IsDowngrade() bool {
+	cmp, err := semver.Compare(c.To, c.From)
+	return err == nil && cmp < 0
+}
+
+// Changes describes how a dependency graph changed between two lockfiles
+type Changes struct {
+	Added    []PackageRef     // Versions only in the new graph
+	Removed  []PackageRef     // Versions only in the old graph
+	Upgraded []VersionChange  // Packages whose single version changed (downgrades included)
+	NewPaths []DependencyPath // One shortest chain through each new parent → child link
+
+	introduced map[PackageRef]bool
+}
+
+// IsIntroduced reports whether the new graph brings in this package version
+func (c *Changes) IsIntroduced(name, version string) bool {
+	return c.introduced[PackageRef{Name: name, Version: version}]
+}
+
+// Introduced returns every package version the new graph brings in, sorted
+func (c *Changes) Introduced() []PackageRef {
+	refs := make([]PackageRef, 0, len(c.introduced))
+	for ref := range c.introduced {
+		refs = append(refs, ref)
+	}
+	sortRefs(refs)
+	return refs
+}
+
+// IsEmpty reports whether nothing changed
+func (c *Changes) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Upgraded) == 0 && len(c.NewPaths) == 0
+}
+
+// Diff compares two dependency graphs
+// Packages are compared by name and version, wherever they are installed.
+// Links are compared by package name, so upgrading a package does not make
+// every chain through it new; a new link is reported with a shortest chain
+// from the project through it.
+func Diff(base, head *Graph) *Changes {
+	changes := &Changes{introduced: make(map[PackageRef]bool)}
+
+	baseVersions := versionsByName(base)
+	headVersions := versionsByName(head)
+
+	names := make([]string, 0, len(headVersions)+len(baseVersions))
+	for name := range headVersions {
+		names = append(names, name)
+	}
+	for name := range baseVersions {
+		if _, ok := headVersions[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		added := difference(headVersions[name], baseVersions[name])
+		removed := difference(baseVersions[name], headVersions[name])
+		for _, version := range added {
+			changes.introduced[PackageRef{Name: name, Version: version}] = true
+		}
+
+		if len(added) == 1 && len(removed) == 1 && len(headVersions[name]) == 1 && len(baseVersions[name]) == 1 {
+			changes.Upgraded = append(changes.Upgraded, VersionChange{Name: name, From: removed[0], To: added[0]})
+			continue
+		}
+		for _, version := range added {
+			changes.Added = append(changes.Added, PackageRef{Name: name, Version: version})
+		}
+		for _, version := range removed {
+			changes.Removed = append(changes.Removed, PackageRef{Name: name, Version: version})
+		}
+	}
+
+	changes.NewPaths = newPaths(base, head)
+	return changes
+}
+
+// versionsByName collects the installed versions of every package name
+func versionsByName(g *Graph) map[string]map[string]bool {
+	versions := make(map[string]map[string]bool)
+	for _, node := range g.Nodes {
+		if versions[node.Package.Name] == nil {
+			versions[node.Package.Name] = make(map[string]bool)
+		}
+		versions[node.Package.Name][node.Package.Version] = true
+	}
+	return versions
+}
+
+// difference returns the versions in a but not in b, sorted
+func difference(a, b map[string]bool) []string {
+	out := make([]string, 0)
+	for version := range a {
+		if !b[version] {
+			out = append(out, version)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// newPaths returns one shortest chain through each link the head graph adds
+func newPaths(base, head *Graph) []DependencyPath {
+	baseLinks := links(base)
+
+	paths := make([]DependencyPath, 0)
+	seen := make(map[string]bool)
+	for _, parent := range head.sortedAllNodes() {
+		for _, child := range sortedNodes(parent.Dependencies) {
+			if baseLinks[linkKey(head, parent, child)] {
+				continue
+			}
+
+			chain, _ := head.AllPaths(parent, 1)
+			if len(chain) == 0 {
+				continue // The project never reaches this link
+			}
+			path := append(chain[0].Names(), child.Package.Name)
+
+			key := strings.Join(path, "\x00")
+			if !seen[key] {
+				seen[key] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	sort.SliceStable(paths, func(i, j int) bool {
+		return strings.Join(paths[i], "\x00") < strings.Join(paths[j], "\x00")
+	})
+	return paths
+}
+
+// links returns the parent → child name pairs of a graph
+func links(g *Graph) map[string]bool {
+	set := make(map[string]bool)
+	for _, parent := range g.sortedAllNodes() {
+		for _, child := range parent.Dependencies {
+			set[linkKey(g, parent, child)] = true
+		}
+	}
+	return set
+}
+
+// linkKey identifies a link by package names; the project itself has no name
+// so renaming it does not change every direct dependency link
+func linkKey(g *Graph, parent, child *Node) string {
+	name := parent.Package.Name
+	if parent == g.Root {
+		name = ""
+	}
+	return name + "\x00" + child.Package.Name
+}
+
+// sortRefs orders package references by name, then version
+func sortRefs(refs []PackageRef) {
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Name != refs[j].Name {
+			return refs[i].Name < refs[j].Name
+		}
+		return refs[i].Version < refs[j].Version
+	})
+}
@@ -0,0 +1,72 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+// withVersion sets the version of a test graph node
+func withVersion(g *Graph, name, version string) {
+	g.Nodes["node_modules/"+name].Package.Version = version
+}
+
+func TestDiff(t *testing.T) {
+	// Arrange
+	base := testGraph([][2]string{
+		{"root", "express"}, {"express", "debug"}, {"root", "left-pad"},
+	})
+	head := testGraph([][2]string{
+		{"root", "express"}, {"express", "debug"}, {"express", "evil"}, {"root", "lodash"},
+	})
+	withVersion(base, "express", "4.18.2")
+	withVersion(head, "express", "4.19.0")
+
+	// Act
+	changes := Diff(base, head)
+
+	// Assert
+	assert.Equal(t, []PackageRef{{Name: "evil", Version: "1.0.0"}, {Name: "lodash", Version: "1.0.0"}}, changes.Added)
+	assert.Equal(t, []PackageRef{{Name: "left-pad", Version: "1.0.0"}}, changes.Removed)
+	assert.Equal(t, []VersionChange{{Name: "express", From: "4.18.2", To: "4.19.0"}}, changes.Upgraded)
+	assert.Equal(t, []DependencyPath{
+		{"root", "express", "evil"},
+		{"root", "lodash"},
+	}, changes.NewPaths, "the upgrade alone adds no path")
+
+	assert.True(t, changes.IsIntroduced("express", "4.19.0"))
+	assert.True(t, changes.IsIntroduced("evil", "1.0.0"))
+	assert.False(t, changes.IsIntroduced("debug", "1.0.0"))
+	assert.Len(t, changes.Introduced(), 3)
+	assert.False(t, changes.IsEmpty())
+}
+
+func TestDiff_Unchanged(t *testing.T) {
+	edges := [][2]string{{"root", "a"}, {"a", "b"}, {"b", "a"}}
+
+	changes := Diff(testGraph(edges), testGraph(edges))
+
+	assert.True(t, changes.IsEmpty())
+	assert.Empty(t, changes.Introduced())
+}
+
+func TestDiff_SecondCopy(t *testing.T) {
+	// Arrange - a second, nested copy of debug is installed next to the first
+	base := testGraph([][2]string{{"root", "debug"}})
+	head := testGraph([][2]string{{"root", "debug"}})
+	head.Nodes["node_modules/ms/node_modules/debug"] = &Node{Package: &parser.Package{Name: "debug", Version: "2.0.0"}}
+
+	// Act
+	changes := Diff(base, head)
+
+	// Assert
+	assert.Equal(t, []PackageRef{{Name: "debug", Version: "2.0.0"}}, changes.Added)
+	assert.Empty(t, changes.Upgraded, "two versions installed is not an upgrade")
+}
+
+func TestVersionChange_IsDowngrade(t *testing.T) {
+	assert.True(t, VersionChange{From: "2.0.0", To: "1.9.0"}.IsDowngrade())
+	assert.False(t, VersionChange{From: "1.9.0", To: "2.0.0"}.IsDowngrade())
+	assert.False(t, VersionChange{From: "latest", To: "1.0.0"}.IsDowngrade(), "unparsable versions are not downgrades")
+}
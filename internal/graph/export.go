@@ -0,0 +1,306 @@
+package graph
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Export formats
+const (
+	ExportDOT     = "dot"
+	ExportMermaid = "mermaid"
+	ExportGraphML = "graphml"
+	ExportJSON    = "json"
+)
+
+// ExportFormats lists the formats Export can write
+func ExportFormats() []string {
+	return []string{ExportDOT, ExportMermaid, ExportGraphML, ExportJSON}
+}
+
+// ExportOptions selects and decorates the nodes Export writes
+type ExportOptions struct {
+	Focus     []*Node          // Only keep nodes on a chain from the root to one of these (nil keeps everything)
+	Highlight func(*Node) bool // Nodes to highlight, e.g. blocklisted packages (nil highlights nothing)
+}
+
+// exportNode is a node as written by Export
+type exportNode struct {
+	ID          string `json:"id"`
+	Path        string `json:"path"`
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Depth       int    `json:"depth"`
+	Direct      bool   `json:"direct"`
+	Root        bool   `json:"root,omitempty"`
+	Highlighted bool   `json:"highlighted,omitempty"`
+}
+
+// exportEdge is a dependency edge as written by Export
+type exportEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// exportGraph is the format-independent view Export serializes
+type exportGraph struct {
+	Nodes []exportNode `json:"nodes"`
+	Edges []exportEdge `json:"edges"`
+}
+
+// Export writes the graph in one of ExportFormats
+func Export(w io.Writer, g *Graph, format string, opts ExportOptions) error {
+	view := newExportGraph(g, opts)
+
+	switch format {
+	case ExportDOT:
+		return writeDOT(w, view)
+	case ExportMermaid:
+		return writeMermaid(w, view)
+	case ExportGraphML:
+		return writeGraphML(w, view)
+	case ExportJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(view)
+	default:
+		return fmt.Errorf("unknown graph format %q (supported: %s)", format, strings.Join(ExportFormats(), ", "))
+	}
+}
+
+// newExportGraph numbers the kept nodes (root first, then by package path) and collects their edges
+func newExportGraph(g *Graph, opts ExportOptions) exportGraph {
+	keep := func(*Node) bool { return true }
+	if opts.Focus != nil {
+		onPath := g.focusSet(opts.Focus)
+		keep = func(node *Node) bool { return onPath[node] }
+	}
+
+	paths := make([]string, 0, len(g.Nodes))
+	for path, node := range g.Nodes {
+		if keep(node) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var view exportGraph
+	ids := make(map[*Node]string)
+	add := func(path string, node *Node, root bool) {
+		id := fmt.Sprintf("n%d", len(view.Nodes))
+		ids[node] = id
+		view.Nodes = append(view.Nodes, exportNode{
+			ID:          id,
+			Path:        path,
+			Name:        node.Package.Name,
+			Version:     node.Package.Version,
+			Depth:       node.Depth,
+			Direct:      node.IsDirect,
+			Root:        root,
+			Highlighted: opts.Highlight != nil && opts.Highlight(node),
+		})
+	}
+
+	if keep(g.Root) {
+		add("", g.Root, true)
+	}
+	for _, path := range paths {
+		add(path, g.Nodes[path], false)
+	}
+
+	view.Edges = make([]exportEdge, 0)
+	for _, from := range append([]*Node{g.Root}, nodesByPath(g, paths)...) {
+		if _, ok := ids[from]; !ok {
+			continue
+		}
+		for _, to := range sortedNodes(from.Dependencies) {
+			if _, ok := ids[to]; ok {
+				view.Edges = append(view.Edges, exportEdge{From: ids[from], To: ids[to]})
+			}
+		}
+	}
+	return view
+}
+
+// focusSet returns the nodes on some chain from the root to one of targets
+func (g *Graph) focusSet(targets []*Node) map[*Node]bool {
+	fromRoot := map[*Node]bool{g.Root: true}
+	pending := []*Node{g.Root}
+	for len(pending) > 0 {
+		node := pending[0]
+		pending = pending[1:]
+		for _, dep := range node.Dependencies {
+			if !fromRoot[dep] {
+				fromRoot[dep] = true
+				pending = append(pending, dep)
+			}
+		}
+	}
+
+	onPath := make(map[*Node]bool)
+	for _, target := range targets {
+		for node := range reachers(target) {
+			if fromRoot[node] {
+				onPath[node] = true
+			}
+		}
+	}
+	return onPath
+}
+
+// nodesByPath looks up nodes for already sorted package paths
+func nodesByPath(g *Graph, paths []string) []*Node {
+	nodes := make([]*Node, 0, len(paths))
+	for _, path := range paths {
+		nodes = append(nodes, g.Nodes[path])
+	}
+	return nodes
+}
+
+// label formats a node as name@version
+func (n exportNode) label() string {
+	if n.Version == "" {
+		return n.Name
+	}
+	return n.Name + "@" + n.Version
+}
+
+// writeDOT writes a Graphviz digraph
+func writeDOT(w io.Writer, view exportGraph) error {
+	quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+	var b strings.Builder
+	fmt.Fprintln(&b, "digraph dependencies {")
+	fmt.Fprintln(&b, "  rankdir=LR;")
+	fmt.Fprintln(&b, "  node [shape=box, fontname=\"Helvetica\"];")
+	for _, node := range view.Nodes {
+		attrs := fmt.Sprintf("label=\"%s\"", quote.Replace(node.label()))
+		switch {
+		case node.Highlighted:
+			attrs += `, style=filled, fillcolor="#f8d7da", color="#dc3545", penwidth=2`
+		case node.Root:
+			attrs += `, style=bold`
+		case node.Direct:
+			attrs += `, style=filled, fillcolor="#e7f1ff"`
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", node.ID, attrs)
+	}
+	for _, edge := range view.Edges {
+		fmt.Fprintf(&b, "  %s -> %s;\n", edge.From, edge.To)
+	}
+	fmt.Fprintln(&b, "}")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeMermaid writes a Mermaid flowchart
+func writeMermaid(w io.Writer, view exportGraph) error {
+	quote := strings.NewReplacer(`"`, "#quot;")
+
+	var b strings.Builder
+	fmt.Fprintln(&b, "graph LR")
+	highlighted := make([]string, 0)
+	for _, node := range view.Nodes {
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", node.ID, quote.Replace(node.label()))
+		if node.Highlighted {
+			highlighted = append(highlighted, node.ID)
+		}
+	}
+	for _, edge := range view.Edges {
+		fmt.Fprintf(&b, "  %s --> %s\n", edge.From, edge.To)
+	}
+	if len(highlighted) > 0 {
+		fmt.Fprintln(&b, "  classDef flagged fill:#f8d7da,stroke:#dc3545,stroke-width:2px,color:#000")
+		fmt.Fprintf(&b, "  class %s flagged\n", strings.Join(highlighted, ","))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// GraphML document structure
+type graphML struct {
+	XMLName xml.Name         `xml:"graphml"`
+	XMLNS   string           `xml:"xmlns,attr"`
+	Keys    []graphMLKey     `xml:"key"`
+	Graph   graphMLGraphBody `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	Name     string `xml:"attr.name,attr"`
+	Type     string `xml:"attr.type,attr"`
+	Fallback string `xml:"default,omitempty"`
+}
+
+type graphMLGraphBody struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// writeGraphML writes a GraphML document (yEd, Gephi, Cytoscape)
+func writeGraphML(w io.Writer, view exportGraph) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "name", For: "node", Name: "name", Type: "string"},
+			{ID: "version", For: "node", Name: "version", Type: "string"},
+			{ID: "path", For: "node", Name: "path", Type: "string"},
+			{ID: "depth", For: "node", Name: "depth", Type: "int"},
+			{ID: "direct", For: "node", Name: "direct", Type: "boolean", Fallback: "false"},
+			{ID: "highlighted", For: "node", Name: "highlighted", Type: "boolean", Fallback: "false"},
+		},
+		Graph: graphMLGraphBody{ID: "dependencies", EdgeDefault: "directed"},
+	}
+
+	for _, node := range view.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: node.ID,
+			Data: []graphMLData{
+				{Key: "name", Value: node.Name},
+				{Key: "version", Value: node.Version},
+				{Key: "path", Value: node.Path},
+				{Key: "depth", Value: fmt.Sprintf("%d", node.Depth)},
+				{Key: "direct", Value: fmt.Sprintf("%t", node.Direct)},
+				{Key: "highlighted", Value: fmt.Sprintf("%t", node.Highlighted)},
+			},
+		})
+	}
+	for _, edge := range view.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{Source: edge.From, Target: edge.To})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode GraphML: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,123 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exportTestGraph has evil reached through a, and an unrelated branch b
+func exportTestGraph() *Graph {
+	g := testGraph([][2]string{
+		{"root", "a"}, {"root", "b"},
+		{"a", "evil"}, {"b", "c"},
+	})
+	g.Nodes["node_modules/a"].IsDirect = true
+	g.Nodes["node_modules/b"].IsDirect = true
+	return g
+}
+
+func TestExport_JSON(t *testing.T) {
+	// Arrange
+	g := exportTestGraph()
+
+	// Act
+	var buf bytes.Buffer
+	err := Export(&buf, g, ExportJSON, ExportOptions{})
+
+	// Assert
+	require.NoError(t, err)
+
+	var view exportGraph
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &view))
+	require.Len(t, view.Nodes, 5)
+	assert.True(t, view.Nodes[0].Root)
+	assert.Equal(t, "node_modules/a", view.Nodes[1].Path)
+	assert.True(t, view.Nodes[1].Direct)
+	assert.Len(t, view.Edges, 4)
+	assert.Equal(t, exportEdge{From: "n0", To: "n1"}, view.Edges[0])
+}
+
+func TestExport_Focus(t *testing.T) {
+	// Arrange
+	g := exportTestGraph()
+	evil := g.Nodes["node_modules/evil"]
+
+	// Act
+	var buf bytes.Buffer
+	err := Export(&buf, g, ExportJSON, ExportOptions{
+		Focus:     []*Node{evil},
+		Highlight: func(node *Node) bool { return node == evil },
+	})
+
+	// Assert
+	require.NoError(t, err)
+
+	var view exportGraph
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &view))
+	names := make([]string, 0, len(view.Nodes))
+	for _, node := range view.Nodes {
+		names = append(names, node.Name)
+	}
+	assert.Equal(t, []string{"root", "a", "evil"}, names, "the unrelated branch is dropped")
+	assert.Len(t, view.Edges, 2)
+	assert.True(t, view.Nodes[2].Highlighted)
+	assert.False(t, view.Nodes[1].Highlighted)
+}
+
+func TestExport_Formats(t *testing.T) {
+	g := exportTestGraph()
+	evil := g.Nodes["node_modules/evil"]
+	opts := ExportOptions{Highlight: func(node *Node) bool { return node == evil }}
+
+	tests := []struct {
+		format   string
+		contains []string
+	}{
+		{
+			format:   ExportDOT,
+			contains: []string{"digraph dependencies {", `n4 [label="evil@1.0.0", style=filled, fillcolor="#f8d7da"`, "n0 -> n1;"},
+		},
+		{
+			format:   ExportMermaid,
+			contains: []string{"graph LR", `n4["evil@1.0.0"]`, "n0 --> n1", "class n4 flagged"},
+		},
+		{
+			format:   ExportGraphML,
+			contains: []string{`<graph id="dependencies" edgedefault="directed">`, `<edge source="n0" target="n1"></edge>`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, Export(&buf, g, tt.format, opts))
+
+			for _, want := range tt.contains {
+				assert.Contains(t, buf.String(), want)
+			}
+		})
+	}
+}
+
+func TestExport_GraphMLIsValidXML(t *testing.T) {
+	g := testGraph([][2]string{{"root", `we<ird&"pkg`}})
+
+	var buf bytes.Buffer
+	require.NoError(t, Export(&buf, g, ExportGraphML, ExportOptions{}))
+
+	var doc graphML
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	require.Len(t, doc.Graph.Nodes, 2)
+	assert.Equal(t, `we<ird&"pkg`, doc.Graph.Nodes[1].Data[0].Value)
+}
+
+func TestExport_UnknownFormat(t *testing.T) {
+	err := Export(&bytes.Buffer{}, exportTestGraph(), "png", ExportOptions{})
+
+	assert.ErrorContains(t, err, `unknown graph format "png"`)
+}
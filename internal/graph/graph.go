@@ -1,6 +1,8 @@
 package graph
 
 import (
+	"sort"
+
 	"github.com/fullstack-spiderman/hulud-scan/internal/parser"
 )
 
@@ -69,6 +71,8 @@ func BuildGraph(lockfile *parser.Lockfile) (*Graph, error) {
 }
 
 // calculateDepth performs BFS to calculate depth and mark direct dependencies
+// Each node is enqueued once, so dependency cycles cannot loop; nodes the
+// root never reaches get depth 999.
 func calculateDepth(graph *Graph, lockfile *parser.Lockfile) {
 	// Queue for BFS: [node, depth]
 	type queueItem struct {
@@ -76,52 +80,6 @@ func calculateDepth(graph *Graph, lockfile *parser.Lockfile) {
 		depth int
 	}
 
-	// Find all direct dependencies by looking at root project's dependencies
-	// In package-lock.json v3, the root package has empty string key with dependencies
-	// But we're building from our Root node, so we need to find which packages
-	// are direct vs transitive
-
-	// Direct dependencies are those at depth 1 from root
-	// We'll start by finding packages not depended on by other packages
-	// Actually, better approach: assume all packages are direct unless proven otherwise
-
-	// Mark packages that are depended on by other packages as transitive
-	for _, node := range graph.Nodes {
-		for _, dep := range node.Dependencies {
-			// This dep is depended on by node, so it's transitive from node's perspective
-			// But we need to check if node itself is direct
-			// This is getting complex - let's use a simpler approach
-			_ = dep // Explicitly mark as unused in this loop
-		}
-	}
-
-	// Actually, simpler approach: all packages at depth 1 are direct
-	// Let's do BFS and mark as we go
-
-	// Start with root's dependencies
-	// We need to infer root's dependencies from the graph
-	// Root depends on packages that aren't depended on by any package in node_modules
-	// Or: packages at the top level of node_modules
-
-	// Even simpler: just traverse from root, marking depth
-	// Direct = depth 1
-
-	// Initialize: all packages start at depth -1
-	// Root is depth 0
-	visited := make(map[*Node]bool)
-
-	// We need to connect root to its direct dependencies
-	// In our lockfile, direct dependencies are those not nested
-	// Let's assume all packages in graph.Nodes are potentially reachable
-	// and find shortest path to each
-
-	// Better approach: Use BFS from each package upward through Dependents
-	// to find distance to root
-
-	// Actually, let's use a working approach:
-	// 1. Top-level packages (node_modules/X without further nesting in path) are direct
-	// 2. Calculate depth from root
-
 	// Mark direct dependencies using the lockfile's DirectDependencies map
 	for depName := range lockfile.DirectDependencies {
 		// Find the node for this direct dependency
@@ -136,9 +94,9 @@ func calculateDepth(graph *Graph, lockfile *parser.Lockfile) {
 		}
 	}
 
-	// Now calculate depth for transitive dependencies using BFS
+	// Now calculate depth for transitive dependencies using BFS from the root
+	visited := map[*Node]bool{graph.Root: true}
 	queue := []queueItem{{node: graph.Root, depth: 0}}
-	visited[graph.Root] = true
 
 	for len(queue) > 0 {
 		// Dequeue
@@ -164,7 +122,7 @@ func calculateDepth(graph *Graph, lockfile *parser.Lockfile) {
 		}
 	}
 
-	// Handle any unvisited nodes (shouldn't happen in a well-formed graph)
+	// Handle any unvisited nodes (orphans, or cycles nothing else depends on)
 	for _, node := range graph.Nodes {
 		if node.Depth == -1 {
 			node.Depth = 999 // Mark as unreachable
@@ -173,7 +131,8 @@ func calculateDepth(graph *Graph, lockfile *parser.Lockfile) {
 }
 
 // FindPath finds a dependency path from root to the specified package
-// Returns the path as a list of package names
+// Returns the path as a list of package names. Each node is visited once,
+// so dependency cycles are safe.
 func (g *Graph) FindPath(targetPath string) DependencyPath {
 	targetNode, exists := g.Nodes[targetPath]
 	if !exists {
@@ -224,3 +183,153 @@ func (g *Graph) FindPath(targetPath string) DependencyPath {
 	// Target not reachable from root
 	return nil
 }
+
+// FindNodes returns the nodes for a package name, optionally only one version,
+// sorted by package path
+func (g *Graph) FindNodes(name, version string) []*Node {
+	paths := make([]string, 0)
+	for path, node := range g.Nodes {
+		if node.Package.Name == name && (version == "" || node.Package.Version == version) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	nodes := make([]*Node, 0, len(paths))
+	for _, path := range paths {
+		nodes = append(nodes, g.Nodes[path])
+	}
+	return nodes
+}
+
+// maxPathSearch bounds the partial chains AllPaths expands, so a large and
+// densely connected graph cannot stall a scan
+const maxPathSearch = 100000
+
+// AllPaths returns every distinct chain from the root to target, shortest first
+// Chains never visit a node twice, so cycles are safe. At most limit chains
+// are returned (0 means no limit); truncated reports whether more exist.
+func (g *Graph) AllPaths(target *Node, limit int) (paths []NodePath, truncated bool) {
+	return g.AllPathsFrom(g.Root, target, limit)
+}
+
+// AllPathsFrom is AllPaths with the chains starting at start instead of the root
+func (g *Graph) AllPathsFrom(start, target *Node, limit int) (paths []NodePath, truncated bool) {
+	// Only nodes that can reach the target are worth walking into
+	reaches := reachers(target)
+	if !reaches[start] {
+		return nil, false
+	}
+
+	// Breadth-first over partial chains, so shorter chains come out first
+	queue := []NodePath{{start}}
+	for searched := 0; len(queue) > 0; searched++ {
+		if searched == maxPathSearch {
+			return paths, true
+		}
+
+		path := queue[0]
+		queue = queue[1:]
+
+		last := path[len(path)-1]
+		if last == target {
+			if limit > 0 && len(paths) == limit {
+				return paths, true
+			}
+			paths = append(paths, path)
+			continue
+		}
+
+		for _, dep := range sortedNodes(last.Dependencies) {
+			if reaches[dep] && !path.contains(dep) {
+				next := make(NodePath, len(path)+1)
+				copy(next, path)
+				next[len(path)] = dep
+				queue = append(queue, next)
+			}
+		}
+	}
+	return paths, false
+}
+
+// Workspaces returns the workspace packages linked into node_modules, sorted by name
+// npm records a workspace as a link entry pointing at the folder's own entry;
+// nothing depends on the folder entry, so it is a root of its own.
+func (g *Graph) Workspaces() []*Node {
+	seen := make(map[*Node]bool)
+	workspaces := make([]*Node, 0)
+	for _, node := range g.Nodes {
+		if !node.Package.Link {
+			continue
+		}
+		if target, ok := g.Nodes[node.Package.Resolved]; ok && !seen[target] {
+			seen[target] = true
+			workspaces = append(workspaces, target)
+		}
+	}
+	return sortedNodes(workspaces)
+}
+
+// EntryPoints returns the direct dependencies through which target is reached,
+// sorted by name: upgrading or removing one of them cuts those chains
+// A direct dependency that is itself the target is its own entry point.
+func (g *Graph) EntryPoints(target *Node) []*Node {
+	reaches := reachers(target)
+
+	entries := make([]*Node, 0)
+	for _, dep := range sortedNodes(g.Root.Dependencies) {
+		if reaches[dep] {
+			entries = append(entries, dep)
+		}
+	}
+	return entries
+}
+
+// reachers returns the set of nodes from which target can be reached,
+// including target itself
+func reachers(target *Node) map[*Node]bool {
+	reaches := map[*Node]bool{target: true}
+	pending := []*Node{target}
+	for len(pending) > 0 {
+		node := pending[0]
+		pending = pending[1:]
+		for _, dependent := range node.Dependents {
+			if !reaches[dependent] {
+				reaches[dependent] = true
+				pending = append(pending, dependent)
+			}
+		}
+	}
+	return reaches
+}
+
+// Names converts a chain of nodes into the package names along it
+func (p NodePath) Names() DependencyPath {
+	names := make(DependencyPath, len(p))
+	for i, node := range p {
+		names[i] = node.Package.Name
+	}
+	return names
+}
+
+// contains reports whether a node is already on the path
+func (p NodePath) contains(node *Node) bool {
+	for _, n := range p {
+		if n == node {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedNodes returns nodes ordered by name and version, for stable output
+func sortedNodes(nodes []*Node) []*Node {
+	sorted := append([]*Node(nil), nodes...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Package.Name != sorted[j].Package.Name {
+			return sorted[i].Package.Name < sorted[j].Package.Name
+		}
+		return sorted[i].Package.Version < sorted[j].Package.Version
+	})
+	return sorted
+}
@@ -1,6 +1,7 @@
 package graph
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/fullstack-spiderman/hulud-scan/internal/parser"
@@ -101,3 +102,136 @@ func TestFindDependencyPath_DirectDependency(t *testing.T) {
 	assert.Equal(t, "test-clean", path[0])
 	assert.Equal(t, "lodash", path[1])
 }
+
+// testGraph builds a graph from "from -> to" edges between package names
+// "root" is the project; every other name becomes node_modules/<name>@1.0.0.
+func testGraph(edges [][2]string) *Graph {
+	g := &Graph{
+		Root:  &Node{Package: &parser.Package{Name: "root", Version: "1.0.0"}},
+		Nodes: make(map[string]*Node),
+	}
+	node := func(name string) *Node {
+		if name == "root" {
+			return g.Root
+		}
+		path := "node_modules/" + name
+		if g.Nodes[path] == nil {
+			g.Nodes[path] = &Node{Package: &parser.Package{Name: name, Version: "1.0.0"}}
+		}
+		return g.Nodes[path]
+	}
+
+	for _, edge := range edges {
+		from, to := node(edge[0]), node(edge[1])
+		from.Dependencies = append(from.Dependencies, to)
+		to.Dependents = append(to.Dependents, from)
+	}
+	return g
+}
+
+// names renders a node path as "a > b > c"
+func names(path NodePath) string {
+	return strings.Join(path.Names(), " > ")
+}
+
+func TestAllPaths(t *testing.T) {
+	// Arrange - a diamond (root > a|b > c > evil), a shortcut and a cycle (c <-> d)
+	g := testGraph([][2]string{
+		{"root", "a"}, {"root", "b"}, {"root", "evil"},
+		{"a", "c"}, {"b", "c"}, {"c", "evil"},
+		{"c", "d"}, {"d", "c"}, {"d", "evil"},
+		{"root", "unrelated"},
+	})
+	evil := g.Nodes["node_modules/evil"]
+
+	// Act
+	paths, truncated := g.AllPaths(evil, 0)
+
+	// Assert
+	rendered := make([]string, 0, len(paths))
+	for _, path := range paths {
+		rendered = append(rendered, names(path))
+	}
+	assert.False(t, truncated)
+	assert.Equal(t, []string{
+		"root > evil",
+		"root > a > c > evil",
+		"root > b > c > evil",
+		"root > a > c > d > evil",
+		"root > b > c > d > evil",
+	}, rendered)
+}
+
+func TestAllPaths_Limit(t *testing.T) {
+	g := testGraph([][2]string{
+		{"root", "a"}, {"root", "b"}, {"root", "evil"},
+		{"a", "evil"}, {"b", "evil"},
+	})
+
+	paths, truncated := g.AllPaths(g.Nodes["node_modules/evil"], 2)
+
+	require.Len(t, paths, 2)
+	assert.True(t, truncated)
+	assert.Equal(t, "root > evil", names(paths[0]), "the shortest chains are kept")
+}
+
+func TestAllPaths_Unreachable(t *testing.T) {
+	g := testGraph([][2]string{{"root", "a"}, {"orphan", "evil"}})
+
+	paths, truncated := g.AllPaths(g.Nodes["node_modules/evil"], 0)
+
+	assert.Empty(t, paths)
+	assert.False(t, truncated)
+}
+
+func TestWorkspaces(t *testing.T) {
+	// Arrange - two workspace folders linked into node_modules; only api reaches evil
+	g := testGraph([][2]string{{"root", "a"}, {"api", "evil"}, {"web", "a"}})
+	for _, name := range []string{"api", "web"} {
+		g.Nodes["packages/"+name] = g.Nodes["node_modules/"+name]
+		g.Nodes["node_modules/"+name] = &Node{Package: &parser.Package{Name: name, Resolved: "packages/" + name, Link: true}}
+	}
+	evil := g.Nodes["node_modules/evil"]
+
+	// Act
+	workspaces := g.Workspaces()
+	fromRoot, _ := g.AllPaths(evil, 0)
+	fromAPI, _ := g.AllPathsFrom(workspaces[0], evil, 0)
+
+	// Assert
+	require.Len(t, workspaces, 2)
+	assert.Equal(t, g.Nodes["packages/api"], workspaces[0])
+	assert.Equal(t, g.Nodes["packages/web"], workspaces[1])
+	assert.Empty(t, fromRoot, "nothing links the project to its workspaces")
+	require.Len(t, fromAPI, 1)
+	assert.Equal(t, "api > evil", names(fromAPI[0]))
+}
+
+func TestFindNodes(t *testing.T) {
+	g := testGraph([][2]string{{"root", "a"}, {"a", "evil"}})
+	g.Nodes["node_modules/a/node_modules/evil"] = &Node{Package: &parser.Package{Name: "evil", Version: "2.0.0"}}
+
+	assert.Len(t, g.FindNodes("evil", ""), 2)
+	assert.Len(t, g.FindNodes("evil", "2.0.0"), 1)
+	assert.Empty(t, g.FindNodes("evil", "3.0.0"))
+	assert.Empty(t, g.FindNodes("missing", ""))
+}
+
+func TestEntryPoints(t *testing.T) {
+	// Arrange - evil is reached through a and c, and b is unrelated
+	g := testGraph([][2]string{
+		{"root", "c"}, {"root", "b"}, {"root", "a"},
+		{"a", "x"}, {"x", "evil"}, {"c", "evil"}, {"b", "y"},
+		{"evil", "a"}, // a cycle back into an entry point
+	})
+
+	// Act
+	entries := g.EntryPoints(g.Nodes["node_modules/evil"])
+
+	// Assert
+	got := make([]string, 0, len(entries))
+	for _, node := range entries {
+		got = append(got, node.Package.Name)
+	}
+	assert.Equal(t, []string{"a", "c"}, got)
+}
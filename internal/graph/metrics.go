@@ -0,0 +1,137 @@
+package graph
+
+// Metrics describes how much of the tree a package controls
+type Metrics struct {
+	Dependents     int     // Packages that depend on it, directly or transitively
+	EntryPoints    int     // Direct dependencies of the project it is reachable from
+	FanOut         int     // Packages it depends on directly
+	Dominators     []*Node // Packages every chain from the project passes through, closest to the project first
+	DominatorDepth int     // len(Dominators); 0 for direct dependencies and unreachable packages
+	Dominates      int     // Packages only reachable through it (its dominator subtree)
+	Reachable      bool    // Is it reachable from the project at all?
+}
+
+// Metrics computes the metrics of every package node, keyed by node
+// Dominators are computed with the Cooper-Harvey-Kennedy algorithm over the
+// chains from the root, so they are exact even when the graph has cycles.
+func (g *Graph) Metrics() map[*Node]*Metrics {
+	nodes := make([]*Node, 0, len(g.Nodes))
+	for _, node := range g.Nodes {
+		nodes = append(nodes, node)
+	}
+	return g.MetricsFor(nodes)
+}
+
+// MetricsFor computes the metrics of some package nodes only, keyed by node
+// Counting dependents walks the graph once per node, so reports that only
+// describe flagged packages should not pay for every node in the tree.
+func (g *Graph) MetricsFor(nodes []*Node) map[*Node]*Metrics {
+	direct := make(map[*Node]bool)
+	for _, dep := range g.Root.Dependencies {
+		direct[dep] = true
+	}
+
+	idom := g.immediateDominators()
+	dominated := make(map[*Node][]*Node) // Dominator tree: node -> nodes it immediately dominates
+	for node, d := range idom {
+		if node != g.Root {
+			dominated[d] = append(dominated[d], node)
+		}
+	}
+
+	metrics := make(map[*Node]*Metrics, len(nodes))
+	for _, node := range nodes {
+		m := &Metrics{FanOut: len(node.Dependencies)}
+		for reacher := range reachers(node) {
+			if reacher != node && reacher != g.Root {
+				m.Dependents++
+			}
+			if direct[reacher] {
+				m.EntryPoints++
+			}
+		}
+
+		if _, ok := idom[node]; ok {
+			m.Reachable = true
+			for d := idom[node]; d != g.Root; d = idom[d] {
+				m.Dominators = append([]*Node{d}, m.Dominators...)
+			}
+			m.DominatorDepth = len(m.Dominators)
+			m.Dominates = subtreeSize(dominated, node)
+		}
+		metrics[node] = m
+	}
+	return metrics
+}
+
+// subtreeSize counts the nodes below node in a tree given as children lists
+func subtreeSize(children map[*Node][]*Node, node *Node) int {
+	size := 0
+	pending := append([]*Node(nil), children[node]...)
+	for len(pending) > 0 {
+		next := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		size++
+		pending = append(pending, children[next]...)
+	}
+	return size
+}
+
+// immediateDominators maps every node reachable from the root to its
+// immediate dominator (the root maps to itself)
+func (g *Graph) immediateDominators() map[*Node]*Node {
+	// Number nodes in depth-first postorder
+	postorder := make(map[*Node]int)
+	order := make([]*Node, 0, len(g.Nodes)+1)
+	var visit func(node *Node)
+	visit = func(node *Node) {
+		postorder[node] = -1 // Visiting: guards against cycles
+		for _, dep := range sortedNodes(node.Dependencies) {
+			if _, seen := postorder[dep]; !seen {
+				visit(dep)
+			}
+		}
+		postorder[node] = len(order)
+		order = append(order, node)
+	}
+	visit(g.Root)
+
+	idom := map[*Node]*Node{g.Root: g.Root}
+	intersect := func(a, b *Node) *Node {
+		for a != b {
+			for postorder[a] < postorder[b] {
+				a = idom[a]
+			}
+			for postorder[b] < postorder[a] {
+				b = idom[b]
+			}
+		}
+		return a
+	}
+
+	for changed := true; changed; {
+		changed = false
+		// Reverse postorder, skipping the root (last in postorder)
+		for i := len(order) - 2; i >= 0; i-- {
+			node := order[i]
+
+			var newIdom *Node
+			for _, dependent := range node.Dependents {
+				if _, processed := idom[dependent]; !processed {
+					continue
+				}
+				if newIdom == nil {
+					newIdom = dependent
+				} else {
+					newIdom = intersect(dependent, newIdom)
+				}
+			}
+
+			if newIdom != nil && idom[node] != newIdom {
+				idom[node] = newIdom
+				changed = true
+			}
+		}
+	}
+	return idom
+}
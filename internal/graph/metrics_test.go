@@ -0,0 +1,85 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nodeNames lists the package names of nodes
+func nodeNames(nodes []*Node) []string {
+	out := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		out = append(out, node.Package.Name)
+	}
+	return out
+}
+
+func TestMetrics(t *testing.T) {
+	// Arrange - c is shared by a and b, and everything below c goes through it
+	g := testGraph([][2]string{
+		{"root", "a"}, {"root", "b"},
+		{"a", "c"}, {"b", "c"}, {"a", "f"},
+		{"c", "d"}, {"d", "e"},
+		{"x", "e"}, // x is not reachable from the root
+	})
+	node := func(name string) *Node { return g.Nodes["node_modules/"+name] }
+
+	// Act
+	metrics := g.Metrics()
+
+	// Assert
+	require.Len(t, metrics, 7)
+
+	c := metrics[node("c")]
+	assert.Equal(t, 2, c.Dependents)
+	assert.Equal(t, 2, c.EntryPoints)
+	assert.Equal(t, 1, c.FanOut)
+	assert.Empty(t, c.Dominators, "c is reached through a or b")
+	assert.Equal(t, 2, c.Dominates, "d and e are only reachable through c")
+
+	e := metrics[node("e")]
+	assert.Equal(t, 5, e.Dependents, "a, b, c, d and x")
+	assert.Equal(t, []string{"c", "d"}, nodeNames(e.Dominators))
+	assert.Equal(t, 2, e.DominatorDepth)
+	assert.True(t, e.Reachable)
+
+	f := metrics[node("f")]
+	assert.Equal(t, []string{"a"}, nodeNames(f.Dominators))
+	assert.Equal(t, 1, metrics[node("a")].Dominates)
+
+	x := metrics[node("x")]
+	assert.False(t, x.Reachable)
+	assert.Equal(t, 0, x.EntryPoints)
+	assert.Equal(t, 0, x.Dominates)
+}
+
+func TestMetrics_Cyclic(t *testing.T) {
+	g := buildCyclicGraph(t)
+
+	metrics := g.Metrics()
+
+	es5ext := metrics[g.Nodes["node_modules/es5-ext"]]
+	assert.Equal(t, 6, es5ext.Dominates, "the whole es5-ext subtree hangs off it")
+	assert.Equal(t, 3, es5ext.Dependents, "the rest of its cycle")
+
+	d := metrics[g.Nodes["node_modules/d"]]
+	assert.Equal(t, []string{"es5-ext"}, nodeNames(d.Dominators))
+	assert.Equal(t, 1, d.EntryPoints)
+}
+
+func TestMetricsFor(t *testing.T) {
+	g := testGraph([][2]string{
+		{"root", "a"}, {"root", "b"},
+		{"a", "c"}, {"b", "c"}, {"c", "d"}, {"d", "e"},
+	})
+	c, e := g.Nodes["node_modules/c"], g.Nodes["node_modules/e"]
+
+	metrics := g.MetricsFor([]*Node{c, e})
+
+	require.Len(t, metrics, 2, "only the requested nodes")
+	assert.Equal(t, g.Metrics()[c], metrics[c])
+	assert.Equal(t, g.Metrics()[e], metrics[e])
+	assert.Equal(t, 2, metrics[c].Dominates)
+}
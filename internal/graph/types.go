@@ -8,7 +8,7 @@ type Node struct {
 	Dependencies []*Node         // Packages this one depends on
 	Dependents   []*Node         // Packages that depend on this one
 	IsDirect     bool            // Is this a direct dependency of the root project?
-	Depth        int             // How far from root (0 = direct, 1+ = transitive)
+	Depth        int             // How far from root (1 = direct, 2+ = transitive, 999 = unreachable)
 }
 
 // Graph represents the complete dependency graph
@@ -20,3 +20,6 @@ type Graph struct {
 // DependencyPath represents a chain showing how a package is reached
 // Example: ["my-app", "express", "body-parser", "lodash"]
 type DependencyPath []string
+
+// NodePath is a chain of nodes from the root to a package
+type NodePath []*Node
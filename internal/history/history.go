@@ -0,0 +1,261 @@
+package history
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/graph"
+	"github.com/fullstack-spiderman/hulud-scan/internal/parser"
+	"github.com/fullstack-spiderman/hulud-scan/internal/scanner"
+)
+
+// Revision is a commit that changed a lockfile
+type Revision struct {
+	Commit  string    // Full commit hash
+	Date    time.Time // Committer date
+	Subject string    // First line of the commit message
+	Blob    string    // Object id of the lockfile at this commit ("" when the commit deleted it)
+}
+
+// Short returns the abbreviated commit hash
+func (r Revision) Short() string {
+	if len(r.Commit) > 7 {
+		return r.Commit[:7]
+	}
+	return r.Commit
+}
+
+// Window is a run of consecutive revisions that installed a package version
+type Window struct {
+	From      Revision  // First revision that contained it
+	To        Revision  // Last revision that contained it
+	RemovedBy *Revision // Revision that dropped it, nil if the latest revision still has it
+	Revisions int       // Number of revisions in the window
+}
+
+// Exposure is a blocklisted package version found somewhere in a lockfile's history
+type Exposure struct {
+	Finding scanner.Finding // Finding from the first revision that contained it
+	Windows []Window        // When it was installed, oldest first
+}
+
+// Log lists the commits that changed filename (relative to dir), oldest first
+// since is passed to git as is ("2025-09-01", "3 months ago"); when set, the
+// last revision before it is included too, because that one was still
+// installed when the window opened.
+func Log(dir, filename, since string) ([]Revision, error) {
+	if strings.HasPrefix(since, "-") {
+		return nil, fmt.Errorf("invalid --since value %q", since)
+	}
+
+	args := []string{"log", "--reverse", "--format=%H%x00%cI%x00%s"}
+	if since != "" {
+		args = append(args, "--since="+since)
+	}
+	out, err := runGit(dir, append(args, "--", "./"+filename)...)
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := parseLog(out)
+	if err != nil {
+		return nil, err
+	}
+
+	if since != "" {
+		out, err := runGit(dir, "log", "-1", "--format=%H%x00%cI%x00%s", "--before="+since, "--", "./"+filename)
+		if err != nil {
+			return nil, err
+		}
+		before, err := parseLog(out)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(before, revisions...)
+	}
+
+	// Record which version of the file each commit left behind
+	for i := range revisions {
+		out, err := runGit(dir, "rev-parse", "--verify", "--quiet", revisions[i].Commit+":./"+filename)
+		if err == nil {
+			revisions[i].Blob = strings.TrimSpace(string(out))
+		}
+	}
+	return revisions, nil
+}
+
+// parseLog parses "hash NUL date NUL subject" lines
+func parseLog(out []byte) ([]Revision, error) {
+	revisions := make([]Revision, 0)
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, "\x00", 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected git log line %q", line)
+		}
+		date, err := time.Parse(time.RFC3339, fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("unexpected commit date %q: %w", fields[1], err)
+		}
+		revisions = append(revisions, Revision{Commit: fields[0], Date: date, Subject: fields[2]})
+	}
+	return revisions, nil
+}
+
+// Scan parses every distinct revision of a lockfile, scans it against the
+// blocklist and reports when each blocklisted package version was installed
+// Revisions whose lockfile content did not change are parsed only once.
+// It also returns the number of distinct lockfile versions scanned.
+func Scan(dir, filename string, revisions []Revision, blocklist *scanner.Blocklist) ([]Exposure, int, error) {
+	byBlob := make(map[string]map[graph.PackageRef]scanner.Finding)
+	exposures := make(map[graph.PackageRef]*Exposure)
+	order := make([]graph.PackageRef, 0)
+	open := make(map[graph.PackageRef]*Window)
+
+	for i := range revisions {
+		revision := revisions[i]
+
+		findings, seen := byBlob[revision.Blob]
+		if !seen {
+			var err error
+			if findings, err = scanRevision(dir, filename, revision, blocklist); err != nil {
+				return nil, 0, err
+			}
+			byBlob[revision.Blob] = findings
+		}
+
+		// Close the windows of packages this revision no longer has
+		for ref, window := range open {
+			if _, ok := findings[ref]; !ok {
+				window.RemovedBy = &revisions[i]
+				delete(open, ref)
+			}
+		}
+
+		for _, ref := range sortedRefs(findings) {
+			if window, ok := open[ref]; ok {
+				window.To = revision
+				window.Revisions++
+				continue
+			}
+
+			exposure, ok := exposures[ref]
+			if !ok {
+				exposure = &Exposure{Finding: findings[ref]}
+				exposures[ref] = exposure
+				order = append(order, ref)
+			}
+			exposure.Windows = append(exposure.Windows, Window{From: revision, To: revision, Revisions: 1})
+			open[ref] = &exposure.Windows[len(exposure.Windows)-1]
+		}
+	}
+
+	result := make([]Exposure, 0, len(order))
+	for _, ref := range order {
+		result = append(result, *exposures[ref])
+	}
+	return result, len(byBlob), nil
+}
+
+// sortedRefs returns the package versions of findings in name and version order
+func sortedRefs(findings map[graph.PackageRef]scanner.Finding) []graph.PackageRef {
+	refs := make([]graph.PackageRef, 0, len(findings))
+	for ref := range findings {
+		refs = append(refs, ref)
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Name != refs[j].Name {
+			return refs[i].Name < refs[j].Name
+		}
+		return refs[i].Version < refs[j].Version
+	})
+	return refs
+}
+
+// scanRevision parses the lockfile as of one revision and returns its findings by package version
+func scanRevision(dir, filename string, revision Revision, blocklist *scanner.Blocklist) (map[graph.PackageRef]scanner.Finding, error) {
+	findings := make(map[graph.PackageRef]scanner.Finding)
+	if revision.Blob == "" {
+		return findings, nil // The lockfile was deleted
+	}
+
+	lockfile, err := ParseAt(dir, revision.Commit, filename)
+	if err != nil {
+		return nil, fmt.Errorf("commit %s: %w", revision.Short(), err)
+	}
+	g, err := graph.BuildGraph(lockfile)
+	if err != nil {
+		return nil, fmt.Errorf("commit %s: failed to build graph: %w", revision.Short(), err)
+	}
+
+	for _, finding := range scanner.ScanGraph(g, blocklist).Findings {
+		ref := graph.PackageRef{Name: finding.PackageName, Version: finding.Version}
+		if _, ok := findings[ref]; !ok {
+			findings[ref] = finding
+		}
+	}
+	return findings, nil
+}
+
+// ParseAt parses filename (relative to dir) as it was at a git revision
+// The package.json of that revision is read too, since Yarn, pnpm and Bun
+// lockfiles take the project name and direct dependencies from it.
+func ParseAt(dir, rev, filename string) (*parser.Lockfile, error) {
+	if strings.HasPrefix(rev, "-") {
+		return nil, fmt.Errorf("invalid git revision %q", rev)
+	}
+
+	data, err := readFileAt(dir, rev, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "hulud-history-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := os.WriteFile(filepath.Join(tmpDir, filename), data, 0o600); err != nil {
+		return nil, err
+	}
+	if manifest, err := readFileAt(dir, rev, "package.json"); err == nil {
+		if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), manifest, 0o600); err != nil {
+			return nil, err
+		}
+	}
+
+	lockfile, _, err := parser.ParseFile(filepath.Join(tmpDir, filename))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s at %s: %w", filename, rev, err)
+	}
+	return lockfile, nil
+}
+
+// readFileAt reads a file (relative to dir) as it was at a git revision
+func readFileAt(dir, rev, name string) ([]byte, error) {
+	return runGit(dir, "show", rev+":./"+name)
+}
+
+// runGit runs a git command in dir and returns its standard output
+func runGit(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("git %s: %s", args[0], message)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
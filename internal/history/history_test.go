@@ -0,0 +1,176 @@
+package history
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lockfileWith returns a package-lock.json installing one package
+func lockfileWith(name, version string) string {
+	return `{
+  "name": "app",
+  "version": "1.0.0",
+  "lockfileVersion": 3,
+  "packages": {
+    "": {"name": "app", "version": "1.0.0", "dependencies": {"` + name + `": "` + version + `"}},
+    "node_modules/` + name + `": {"version": "` + version + `"}
+  }
+}
+`
+}
+
+// gitRepo creates a repository; commit writes (or, with content "", deletes) a file and commits it
+func gitRepo(t *testing.T) (dir string, commit func(path, content, message string)) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir = t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	run("init", "-q", "-b", "main")
+
+	return dir, func(path, content, message string) {
+		t.Helper()
+		full := filepath.Join(dir, path)
+		if content == "" {
+			run("rm", "-q", path)
+		} else {
+			require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+			run("add", path)
+		}
+		run("commit", "-q", "--allow-empty", "-m", message)
+	}
+}
+
+// testBlocklist flags evil@1.0.0
+func testBlocklist() *scanner.Blocklist {
+	return scanner.MergeBlocklists(&scanner.Blocklist{Entries: []scanner.BlocklistEntry{
+		{PackageName: "evil", Version: "1.0.0", Severity: scanner.SeverityCritical, Reason: "test"},
+	}})
+}
+
+func TestLog(t *testing.T) {
+	// Arrange
+	dir, commit := gitRepo(t)
+	commit("package-lock.json", lockfileWith("lodash", "4.17.21"), "first")
+	commit("README.md", "readme", "unrelated")
+	commit("package-lock.json", lockfileWith("evil", "1.0.0"), "second")
+
+	// Act
+	revisions, err := Log(dir, "package-lock.json", "")
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, revisions, 2, "commits that did not touch the lockfile are skipped")
+	assert.Equal(t, "first", revisions[0].Subject)
+	assert.Equal(t, "second", revisions[1].Subject)
+	assert.NotEmpty(t, revisions[0].Blob)
+	assert.NotEqual(t, revisions[0].Blob, revisions[1].Blob)
+	assert.Len(t, revisions[0].Short(), 7)
+}
+
+func TestLog_Since(t *testing.T) {
+	// Arrange - lockfile commits in January, March and May
+	dir, _ := gitRepo(t)
+	for _, c := range []struct{ date, version string }{
+		{"2025-01-10T12:00:00Z", "1.0.0"},
+		{"2025-03-10T12:00:00Z", "2.0.0"},
+		{"2025-05-10T12:00:00Z", "3.0.0"},
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "package-lock.json"), []byte(lockfileWith("lodash", c.version)), 0644))
+		for _, args := range [][]string{{"add", "package-lock.json"}, {"commit", "-q", "-m", c.version}} {
+			cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+			cmd.Dir = dir
+			cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+c.date, "GIT_COMMITTER_DATE="+c.date)
+			out, err := cmd.CombinedOutput()
+			require.NoError(t, err, string(out))
+		}
+	}
+
+	// Act
+	revisions, err := Log(dir, "package-lock.json", "2025-04-01")
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, "2.0.0", revisions[0].Subject, "the version installed when the window opened")
+	assert.Equal(t, "3.0.0", revisions[1].Subject)
+}
+
+func TestLog_NoHistory(t *testing.T) {
+	dir, commit := gitRepo(t)
+	commit("README.md", "readme", "first")
+
+	revisions, err := Log(dir, "yarn.lock", "")
+
+	require.NoError(t, err)
+	assert.Empty(t, revisions)
+}
+
+func TestScan(t *testing.T) {
+	// Arrange - evil is installed, removed, reinstalled and finally deleted with the lockfile
+	dir, commit := gitRepo(t)
+	commit("package-lock.json", lockfileWith("lodash", "4.17.21"), "clean")
+	commit("package-lock.json", lockfileWith("evil", "1.0.0"), "add evil")
+	commit("package-lock.json", lockfileWith("evil", "1.0.0")+"\n", "reformat")
+	commit("package-lock.json", lockfileWith("lodash", "4.17.21"), "remove evil")
+	commit("package-lock.json", lockfileWith("evil", "1.0.0"), "add evil again")
+	commit("package-lock.json", "", "delete lockfile")
+
+	revisions, err := Log(dir, "package-lock.json", "")
+	require.NoError(t, err)
+	require.Len(t, revisions, 6)
+
+	// Act
+	exposures, scanned, err := Scan(dir, "package-lock.json", revisions, testBlocklist())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 4, scanned, "identical lockfile contents are parsed once")
+	require.Len(t, exposures, 1)
+	assert.Equal(t, "evil", exposures[0].Finding.PackageName)
+
+	windows := exposures[0].Windows
+	require.Len(t, windows, 2)
+	assert.Equal(t, "add evil", windows[0].From.Subject)
+	assert.Equal(t, "reformat", windows[0].To.Subject)
+	assert.Equal(t, 2, windows[0].Revisions)
+	require.NotNil(t, windows[0].RemovedBy)
+	assert.Equal(t, "remove evil", windows[0].RemovedBy.Subject)
+
+	assert.Equal(t, "add evil again", windows[1].From.Subject)
+	require.NotNil(t, windows[1].RemovedBy)
+	assert.Equal(t, "delete lockfile", windows[1].RemovedBy.Subject)
+}
+
+func TestScan_StillInstalled(t *testing.T) {
+	dir, commit := gitRepo(t)
+	commit("package-lock.json", lockfileWith("evil", "1.0.0"), "add evil")
+
+	revisions, err := Log(dir, "package-lock.json", "")
+	require.NoError(t, err)
+	exposures, _, err := Scan(dir, "package-lock.json", revisions, testBlocklist())
+
+	require.NoError(t, err)
+	require.Len(t, exposures, 1)
+	assert.Nil(t, exposures[0].Windows[0].RemovedBy)
+}
+
+func TestParseAt_RejectsOptionLikeRevision(t *testing.T) {
+	_, err := ParseAt(t.TempDir(), "--output=/tmp/x", "package-lock.json")
+
+	assert.ErrorContains(t, err, "invalid git revision")
+}
@@ -23,20 +23,30 @@ type LockfileInfo struct {
 	Filename string
 }
 
+// lockfiles lists the supported lockfile names in detection priority order
+// (npm is most common)
+var lockfiles = []struct {
+	filename string
+	lockType LockfileType
+}{
+	{"package-lock.json", LockfileTypeNPM},
+	{"yarn.lock", LockfileTypeYarn},
+	{"pnpm-lock.yaml", LockfileTypePNPM},
+	{"bun.lockb", LockfileTypeBun},
+}
+
+// LockfileNames returns the supported lockfile names in detection priority order
+func LockfileNames() []string {
+	names := make([]string, 0, len(lockfiles))
+	for _, lf := range lockfiles {
+		names = append(names, lf.filename)
+	}
+	return names
+}
+
 // DetectLockfile detects which lockfile exists in the project directory
 // Priority order: package-lock.json > yarn.lock > pnpm-lock.yaml > bun.lockb
 func DetectLockfile(projectPath string) (*LockfileInfo, error) {
-	// Check in priority order (npm is most common)
-	lockfiles := []struct {
-		filename string
-		lockType LockfileType
-	}{
-		{"package-lock.json", LockfileTypeNPM},
-		{"yarn.lock", LockfileTypeYarn},
-		{"pnpm-lock.yaml", LockfileTypePNPM},
-		{"bun.lockb", LockfileTypeBun},
-	}
-
 	for _, lf := range lockfiles {
 		lockfilePath := filepath.Join(projectPath, lf.filename)
 		if _, err := os.Stat(lockfilePath); err == nil {
@@ -75,8 +85,24 @@ func ParseAuto(projectPath string) (*Lockfile, *LockfileInfo, error) {
 		return nil, nil, err
 	}
 
-	// Parse based on detected type
+	return parseDetected(info)
+}
+
+// ParseFile parses a lockfile given by path, picking the parser from its file name
+func ParseFile(lockfilePath string) (*Lockfile, *LockfileInfo, error) {
+	filename := filepath.Base(lockfilePath)
+	for _, lf := range lockfiles {
+		if lf.filename == filename {
+			return parseDetected(&LockfileInfo{Type: lf.lockType, Path: lockfilePath, Filename: filename})
+		}
+	}
+	return nil, nil, fmt.Errorf("%s is not a supported lockfile (expected package-lock.json, yarn.lock, pnpm-lock.yaml or bun.lockb)", lockfilePath)
+}
+
+// parseDetected parses a lockfile with the parser for its type
+func parseDetected(info *LockfileInfo) (*Lockfile, *LockfileInfo, error) {
 	var lockfile *Lockfile
+	var err error
 	switch info.Type {
 	case LockfileTypeNPM:
 		lockfile, err = ParseLockfile(info.Path)
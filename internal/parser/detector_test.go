@@ -112,6 +112,23 @@ func TestParseAuto(t *testing.T) {
 	}
 }
 
+func TestParseFile(t *testing.T) {
+	// Act
+	lockfile, info, err := ParseFile("../../testdata/yarn/clean/yarn.lock")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, LockfileTypeYarn, info.Type)
+	assert.Equal(t, "test-yarn-clean", lockfile.Name, "package.json next to the lockfile is still read")
+	assert.Len(t, lockfile.Packages, 4)
+}
+
+func TestParseFile_UnsupportedName(t *testing.T) {
+	_, _, err := ParseFile("../../testdata/npm/clean/package.json")
+
+	assert.ErrorContains(t, err, "not a supported lockfile")
+}
+
 func TestLockfileTypeString(t *testing.T) {
 	tests := []struct {
 		lockType LockfileType
@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Manifest is the part of package.json the scanner uses
+type Manifest struct {
+	Name                 string            `json:"name"`
+	Version              string            `json:"version"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+}
+
+// ReadManifest reads the package.json in dir
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse package.json: %w", err)
+	}
+	return &manifest, nil
+}
+
+// Installed returns the dependencies a package manager installs for the
+// project (dependencies, devDependencies and optionalDependencies), name -> range
+// When a name appears in several groups the later group wins, as in npm.
+func (m *Manifest) Installed() map[string]string {
+	deps := make(map[string]string)
+	for _, group := range []map[string]string{m.Dependencies, m.DevDependencies, m.OptionalDependencies} {
+		for name, spec := range group {
+			deps[name] = spec
+		}
+	}
+	return deps
+}
+
+// Declares reports whether package.json lists name in any dependency group
+func (m *Manifest) Declares(name string) bool {
+	for _, group := range []map[string]string{m.Dependencies, m.DevDependencies, m.OptionalDependencies, m.PeerDependencies} {
+		if _, ok := group[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOptional reports whether name is an optional dependency, which may legitimately not be installed
+func (m *Manifest) IsOptional(name string) bool {
+	_, ok := m.OptionalDependencies[name]
+	return ok
+}
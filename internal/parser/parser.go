@@ -1,9 +1,11 @@
 package parser
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -21,10 +23,15 @@ func ParseLockfile(lockfilePath string) (*Lockfile, error) {
 		Version         string `json:"version"`
 		LockfileVersion int    `json:"lockfileVersion"`
 		Packages        map[string]struct {
-			Version      string            `json:"version"`
-			Resolved     string            `json:"resolved"`
-			Integrity    string            `json:"integrity"`
-			Dependencies map[string]string `json:"dependencies"`
+			Name                 string            `json:"name"`
+			Version              string            `json:"version"`
+			Resolved             string            `json:"resolved"`
+			Integrity            string            `json:"integrity"`
+			Link                 bool              `json:"link"`
+			Dependencies         map[string]string `json:"dependencies"`
+			DevDependencies      map[string]string `json:"devDependencies"`
+			OptionalDependencies map[string]string `json:"optionalDependencies"`
+			PeerDependencies     map[string]string `json:"peerDependencies"`
 		} `json:"packages"`
 	}
 
@@ -32,10 +39,21 @@ func ParseLockfile(lockfilePath string) (*Lockfile, error) {
 		return nil, fmt.Errorf("failed to parse lockfile JSON: %w", err)
 	}
 
+	// Locate each package entry so findings can point at a line
+	lines := npmPackageLines(data)
+
 	// Extract direct dependencies from root package (empty string key)
+	// rootDeps keeps every group the root entry records, for the drift check.
 	directDeps := make(map[string]string)
+	var rootDeps map[string]string
 	if rootPkg, exists := raw.Packages[""]; exists {
 		directDeps = rootPkg.Dependencies
+		rootDeps = make(map[string]string)
+		for _, group := range []map[string]string{rootPkg.PeerDependencies, rootPkg.Dependencies, rootPkg.DevDependencies, rootPkg.OptionalDependencies} {
+			for name, spec := range group {
+				rootDeps[name] = spec
+			}
+		}
 	}
 
 	// Convert to our internal Lockfile structure
@@ -45,6 +63,12 @@ func ParseLockfile(lockfilePath string) (*Lockfile, error) {
 		LockfileVersion:    raw.LockfileVersion,
 		Packages:           make(map[string]*Package),
 		DirectDependencies: directDeps,
+		RootDependencies:   rootDeps,
+	}
+
+	// package.json is optional here: npm lockfiles carry the project name and deps themselves
+	if manifest, err := ReadManifest(filepath.Dir(lockfilePath)); err == nil {
+		lockfile.Manifest = manifest
 	}
 
 	// Process each package
@@ -55,7 +79,11 @@ func ParseLockfile(lockfilePath string) (*Lockfile, error) {
 		}
 
 		// Extract package name from path (e.g., "node_modules/lodash" -> "lodash")
+		// Workspace folders (e.g., "packages/api") are not under node_modules and carry their name.
 		name := extractPackageName(path)
+		if pkg.Name != "" && !strings.Contains(path, "node_modules/") {
+			name = pkg.Name
+		}
 
 		lockfile.Packages[path] = &Package{
 			Name:         name,
@@ -63,12 +91,74 @@ func ParseLockfile(lockfilePath string) (*Lockfile, error) {
 			Resolved:     pkg.Resolved,
 			Integrity:    pkg.Integrity,
 			Dependencies: pkg.Dependencies,
+			Line:         lines[path],
+			Link:         pkg.Link,
 		}
 	}
 
 	return lockfile, nil
 }
 
+// npmPackageLines maps each key of the "packages" object to its line number
+// encoding/json discards positions, so we walk the tokens and track offsets.
+// Errors are ignored: line numbers are best-effort and the full parse already succeeded.
+func npmPackageLines(data []byte) map[string]int {
+	lines := make(map[string]int)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	// Top-level object
+	if tok, err := decoder.Token(); err != nil || tok != json.Delim('{') {
+		return lines
+	}
+
+	for decoder.More() {
+		keyTok, err := decoder.Token()
+		if err != nil {
+			return lines
+		}
+
+		if key, _ := keyTok.(string); key != "packages" {
+			// Skip values we don't need
+			var skip json.RawMessage
+			if err := decoder.Decode(&skip); err != nil {
+				return lines
+			}
+			continue
+		}
+
+		if tok, err := decoder.Token(); err != nil || tok != json.Delim('{') {
+			return lines
+		}
+
+		for decoder.More() {
+			pathTok, err := decoder.Token()
+			if err != nil {
+				return lines
+			}
+
+			// The offset is just past the key, which is on the line we want
+			path, _ := pathTok.(string)
+			lines[path] = lineAt(data, decoder.InputOffset())
+
+			var skip json.RawMessage
+			if err := decoder.Decode(&skip); err != nil {
+				return lines
+			}
+		}
+		return lines
+	}
+
+	return lines
+}
+
+// lineAt returns the 1-based line number of a byte offset
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
 // extractPackageName extracts the package name from a node_modules path
 // e.g., "node_modules/lodash" -> "lodash"
 // e.g., "node_modules/@babel/core" -> "@babel/core"
@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -84,3 +86,75 @@ func TestExtractPackageName(t *testing.T) {
 		})
 	}
 }
+
+func TestParseLockfile_PackageLines(t *testing.T) {
+	lockfile, err := ParseLockfile("../../testdata/npm/affected-transitive/package-lock.json")
+	require.NoError(t, err)
+
+	// Line numbers point at the "node_modules/..." key of each entry
+	assert.Equal(t, 14, lockfile.Packages["node_modules/express"].Line)
+	assert.Equal(t, 24, lockfile.Packages["node_modules/body-parser"].Line)
+	assert.Equal(t, 34, lockfile.Packages["node_modules/02-echo"].Line)
+}
+
+func TestParseLockfile_Workspaces(t *testing.T) {
+	// Arrange - npm links each workspace into node_modules and lists its folder separately
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "package-lock.json"), []byte(`{
+  "name": "mono",
+  "lockfileVersion": 3,
+  "packages": {
+    "": {"name": "mono", "workspaces": ["packages/*"]},
+    "node_modules/@mono/api": {"resolved": "packages/api", "link": true},
+    "packages/api": {"name": "@mono/api", "version": "1.0.0", "dependencies": {"lodash": "^4.17.21"}},
+    "node_modules/lodash": {"version": "4.17.21"}
+  }
+}`), 0644))
+
+	// Act
+	lockfile, err := ParseLockfile(filepath.Join(dir, "package-lock.json"))
+
+	// Assert
+	require.NoError(t, err)
+	link := lockfile.Packages["node_modules/@mono/api"]
+	require.NotNil(t, link)
+	assert.True(t, link.Link)
+	assert.Equal(t, "packages/api", link.Resolved)
+
+	folder := lockfile.Packages["packages/api"]
+	require.NotNil(t, folder)
+	assert.Equal(t, "@mono/api", folder.Name, "workspace folders are named by their entry")
+	assert.False(t, folder.Link)
+	assert.Equal(t, "lodash", lockfile.Packages["node_modules/lodash"].Name)
+}
+
+func TestParseLockfile_RootDependencies(t *testing.T) {
+	// Arrange - the root entry records dev dependencies too
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "package-lock.json"), []byte(`{
+  "name": "app",
+  "lockfileVersion": 3,
+  "packages": {
+    "": {"dependencies": {"lodash": "^4.17.21"}, "devDependencies": {"jest": "^29.0.0"}},
+    "node_modules/lodash": {"version": "4.17.21"},
+    "node_modules/jest": {"version": "29.7.0"}
+  }
+}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"name": "app", "dependencies": {"lodash": "^4.17.21"}}`), 0644))
+
+	// Act
+	lockfile, err := ParseLockfile(filepath.Join(dir, "package-lock.json"))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"lodash": "^4.17.21"}, lockfile.DirectDependencies)
+	assert.Equal(t, map[string]string{"lodash": "^4.17.21", "jest": "^29.0.0"}, lockfile.RootDependencies)
+	require.NotNil(t, lockfile.Manifest)
+	assert.Equal(t, "^4.17.21", lockfile.Manifest.Dependencies["lodash"])
+
+	version, ok := lockfile.ResolveRoot("jest", "^29.0.0")
+	assert.True(t, ok)
+	assert.Equal(t, "29.7.0", version)
+	_, ok = lockfile.ResolveRoot("react", "^18.0.0")
+	assert.False(t, ok)
+}
@@ -17,7 +17,9 @@ func ParsePNPMLock(lockfilePath string) (*Lockfile, error) {
 
 	// pnpm lockfile structure
 	var pnpmLock struct {
-		LockfileVersion interface{} `yaml:"lockfileVersion"`
+		LockfileVersion interface{}             `yaml:"lockfileVersion"`
+		Importers       map[string]pnpmImporter `yaml:"importers"`
+		pnpmImporter    `yaml:",inline"`
 		Packages        map[string]struct {
 			Resolution struct {
 				Integrity string `yaml:"integrity"`
@@ -31,6 +33,9 @@ func ParsePNPMLock(lockfilePath string) (*Lockfile, error) {
 		return nil, fmt.Errorf("failed to parse pnpm-lock.yaml: %w", err)
 	}
 
+	// Locate each package entry so findings can point at a line
+	lines := pnpmPackageLines(data)
+
 	lockfile := &Lockfile{
 		Name:               extractProjectNameFromPath(lockfilePath),
 		Version:            "unknown",
@@ -59,6 +64,7 @@ func ParsePNPMLock(lockfilePath string) (*Lockfile, error) {
 			Version:      version,
 			Integrity:    pkgData.Resolution.Integrity,
 			Dependencies: make(map[string]string),
+			Line:         lines[pkgPath],
 		}
 
 		// Merge dependencies and devDependencies
@@ -72,12 +78,97 @@ func ParsePNPMLock(lockfilePath string) (*Lockfile, error) {
 		lockfile.Packages[nodePath] = pkg
 	}
 
+	// The project's own dependencies: under importers["."] since lockfile v7,
+	// at the top level before that
+	root := pnpmLock.pnpmImporter
+	if importer, ok := pnpmLock.Importers["."]; ok {
+		root = importer
+	}
+	lockfile.RootDependencies, lockfile.Specifiers = root.resolve()
+
 	// Enrich from package.json (non-fatal, continue without enrichment if it fails)
 	_ = enrichFromPackageJSON(lockfilePath, lockfile)
 
 	return lockfile, nil
 }
 
+// pnpmImporter is a project in pnpm-lock.yaml and the dependencies it resolved
+// Since lockfile v6 each dependency is {specifier, version}; v5 has a plain
+// version and keeps the specifiers in a separate map.
+type pnpmImporter struct {
+	Specifiers           map[string]string    `yaml:"specifiers"`
+	Dependencies         map[string]yaml.Node `yaml:"dependencies"`
+	DevDependencies      map[string]yaml.Node `yaml:"devDependencies"`
+	OptionalDependencies map[string]yaml.Node `yaml:"optionalDependencies"`
+}
+
+// resolve returns the importer's dependencies (name -> specifier) and the
+// version each "name@specifier" resolved to; both are nil when it has none
+func (i pnpmImporter) resolve() (deps map[string]string, resolved map[string]string) {
+	for _, group := range []map[string]yaml.Node{i.Dependencies, i.DevDependencies, i.OptionalDependencies} {
+		for name, node := range group {
+			var entry struct {
+				Specifier string `yaml:"specifier"`
+				Version   string `yaml:"version"`
+			}
+			if node.Kind == yaml.MappingNode {
+				if err := node.Decode(&entry); err != nil {
+					continue
+				}
+			} else {
+				entry.Specifier, entry.Version = i.Specifiers[name], node.Value
+			}
+
+			if deps == nil {
+				deps, resolved = make(map[string]string), make(map[string]string)
+			}
+			deps[name] = entry.Specifier
+			resolved[name+"@"+entry.Specifier] = pnpmVersion(entry.Version)
+		}
+	}
+	return deps, resolved
+}
+
+// pnpmVersion strips the peer dependency suffix pnpm appends to resolved versions
+// e.g. "18.2.0(react@18.2.0)" (v6+) or "18.2.0_react@18.2.0" (v5) -> "18.2.0"
+func pnpmVersion(version string) string {
+	if idx := strings.IndexAny(version, "(_"); idx >= 0 {
+		return version[:idx]
+	}
+	return version
+}
+
+// pnpmPackageLines maps each key of the "packages" mapping to its line number
+// Line numbers are best-effort; a decode failure just yields no lines.
+func pnpmPackageLines(data []byte) map[string]int {
+	lines := make(map[string]int)
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return lines
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return lines
+	}
+
+	// Mapping nodes alternate key, value
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "packages" {
+			continue
+		}
+
+		packages := root.Content[i+1]
+		for j := 0; j+1 < len(packages.Content); j += 2 {
+			key := packages.Content[j]
+			lines[key.Value] = key.Line
+		}
+	}
+
+	return lines
+}
+
 // extractPNPMPackageInfo extracts package name and version from pnpm path
 // Examples:
 //   "/lodash/4.17.21" -> "lodash", "4.17.21"
@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -127,3 +129,52 @@ func TestParsePNPMLockfileVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePNPMLock_PackageLines(t *testing.T) {
+	lockfile, err := ParsePNPMLock("../../testdata/pnpm/affected-transitive/pnpm-lock.yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, 13, lockfile.Packages["node_modules/express"].Line)
+	assert.Equal(t, 21, lockfile.Packages["node_modules/02-echo"].Line)
+}
+
+func TestParsePNPMLock_RootDependencies(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"v5", `lockfileVersion: 5.4
+specifiers:
+  react: ^18.0.0
+dependencies:
+  react: 18.2.0_loose-envify@1.4.0
+`},
+		{"v6", `lockfileVersion: '6.0'
+dependencies:
+  react:
+    specifier: ^18.0.0
+    version: 18.2.0
+`},
+		{"v9", `lockfileVersion: '9.0'
+importers:
+  .:
+    devDependencies:
+      react:
+        specifier: ^18.0.0
+        version: 18.2.0(loose-envify@1.4.0)
+`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "pnpm-lock.yaml"), []byte(tt.content), 0644))
+
+			lockfile, err := ParsePNPMLock(filepath.Join(dir, "pnpm-lock.yaml"))
+
+			require.NoError(t, err)
+			assert.Equal(t, map[string]string{"react": "^18.0.0"}, lockfile.RootDependencies)
+			assert.Equal(t, map[string]string{"react@^18.0.0": "18.2.0"}, lockfile.Specifiers)
+		})
+	}
+}
@@ -7,6 +7,8 @@ type Package struct {
 	Resolved     string            // URL where package was downloaded from
 	Integrity    string            // Hash for verification
 	Dependencies map[string]string // Direct dependencies (name -> version range)
+	Line         int               // Line of the package entry in the lockfile (0 if unknown)
+	Link         bool              // npm workspace symlink; Resolved is the path of the linked folder
 }
 
 // Lockfile represents the parsed package-lock.json structure
@@ -16,4 +18,27 @@ type Lockfile struct {
 	LockfileVersion    int                 // npm lockfile format version
 	Packages           map[string]*Package // Map of package path -> Package info
 	DirectDependencies map[string]string   // Direct dependencies from root (name -> version range)
+	RootDependencies   map[string]string   // Every project dependency the lockfile itself records (name -> range); nil if the format has no root entry
+	Specifiers         map[string]string   // Resolved version per "name@range" the lockfile records (yarn, pnpm); nil if it has none
+	Manifest           *Manifest           // package.json next to the lockfile, nil if there is none
+}
+
+// ResolveRoot returns the version the lockfile installs for a project
+// dependency declared as name@spec
+// ok is false when the lockfile has no entry for it.
+func (l *Lockfile) ResolveRoot(name, spec string) (version string, ok bool) {
+	if version, ok := l.Specifiers[name+"@"+spec]; ok {
+		return version, true
+	}
+	if l.RootDependencies != nil {
+		if _, ok := l.RootDependencies[name]; !ok {
+			return "", false
+		}
+	}
+
+	pkg, ok := l.Packages["node_modules/"+name]
+	if !ok {
+		return "", false
+	}
+	return pkg.Version, true
 }
@@ -2,7 +2,6 @@ package parser
 
 import (
 	"bufio"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -28,12 +27,15 @@ func ParseYarnLock(lockfilePath string) (*Lockfile, error) {
 		LockfileVersion:    1, // Yarn lockfile v1
 		Packages:           make(map[string]*Package),
 		DirectDependencies: make(map[string]string),
+		Specifiers:         make(map[string]string),
 	}
 
 	scanner := bufio.NewScanner(file)
 	var currentPackage *Package
 	var currentPath string
+	var currentSpecs []string
 	var inDependencies bool
+	lineNum := 0
 
 	// Regex patterns
 	// Package line must not start with whitespace (to exclude "dependencies:" etc)
@@ -46,6 +48,7 @@ func ParseYarnLock(lockfilePath string) (*Lockfile, error) {
 
 	for scanner.Scan() {
 		line := scanner.Text()
+		lineNum++
 
 		// Skip comments and empty lines
 		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
@@ -57,6 +60,7 @@ func ParseYarnLock(lockfilePath string) (*Lockfile, error) {
 			// Save previous package if exists
 			if currentPackage != nil && currentPath != "" {
 				lockfile.Packages[currentPath] = currentPackage
+				recordYarnSpecs(lockfile, currentSpecs, currentPackage.Version)
 			}
 
 			// Parse package name from "package@version:" format
@@ -65,9 +69,11 @@ func ParseYarnLock(lockfilePath string) (*Lockfile, error) {
 			packageName := extractPackageNameFromSpec(packageSpec)
 
 			currentPath = "node_modules/" + packageName
+			currentSpecs = strings.Split(packageSpec, ",")
 			currentPackage = &Package{
 				Name:         packageName,
 				Dependencies: make(map[string]string),
+				Line:         lineNum,
 			}
 			inDependencies = false
 			continue
@@ -117,6 +123,7 @@ func ParseYarnLock(lockfilePath string) (*Lockfile, error) {
 	// Save last package
 	if currentPackage != nil && currentPath != "" {
 		lockfile.Packages[currentPath] = currentPackage
+		recordYarnSpecs(lockfile, currentSpecs, currentPackage.Version)
 	}
 
 	if err := scanner.Err(); err != nil {
@@ -129,6 +136,14 @@ func ParseYarnLock(lockfilePath string) (*Lockfile, error) {
 	return lockfile, nil
 }
 
+// recordYarnSpecs maps each "name@range" key of a yarn.lock entry to the version it resolved to
+// An entry key may list several specs: "lodash@^4.17.20", "lodash@^4.17.21":
+func recordYarnSpecs(lockfile *Lockfile, specs []string, version string) {
+	for _, spec := range specs {
+		lockfile.Specifiers[strings.Trim(strings.TrimSpace(spec), "\"'")] = version
+	}
+}
+
 // extractPackageNameFromSpec extracts package name from yarn spec
 // Examples:
 //
@@ -167,35 +182,23 @@ func extractProjectNameFromPath(path string) string {
 }
 
 // enrichFromPackageJSON reads package.json to get project info and direct deps
+// The manifest is kept on the lockfile so drift between the two can be checked.
 func enrichFromPackageJSON(lockfilePath string, lockfile *Lockfile) error {
-	// Find package.json in same directory
-	dir := filepath.Dir(lockfilePath)
-	pacakageJSONPath := filepath.Join(dir, "package.json")
-
-	data, err := os.ReadFile(pacakageJSONPath)
+	manifest, err := ReadManifest(filepath.Dir(lockfilePath))
 	if err != nil {
 		return err // Non-fatal
 	}
-
-	var pkgJSON struct {
-		Name         string            `json:"name"`
-		Version      string            `json:"version"`
-		Dependencies map[string]string `json:"dependencies"`
-	}
-
-	if err := json.Unmarshal(data, &pkgJSON); err != nil {
-		return err
-	}
+	lockfile.Manifest = manifest
 
 	// Enrich lockfile
-	if pkgJSON.Name != "" {
-		lockfile.Name = pkgJSON.Name
+	if manifest.Name != "" {
+		lockfile.Name = manifest.Name
 	}
-	if pkgJSON.Version != "" {
-		lockfile.Version = pkgJSON.Version
+	if manifest.Version != "" {
+		lockfile.Version = manifest.Version
 	}
-	if len(pkgJSON.Dependencies) > 0 {
-		lockfile.DirectDependencies = pkgJSON.Dependencies
+	if len(manifest.Dependencies) > 0 {
+		lockfile.DirectDependencies = manifest.Dependencies
 	}
 
 	return nil
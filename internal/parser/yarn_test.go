@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -127,3 +129,37 @@ func TestExtractProjectNameFromPath(t *testing.T) {
 		})
 	}
 }
+
+func TestParseYarnLock_PackageLines(t *testing.T) {
+	lockfile, err := ParseYarnLock("../../testdata/yarn/affected-transitive/yarn.lock")
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, lockfile.Packages["node_modules/02-echo"].Line)
+	assert.Equal(t, 20, lockfile.Packages["node_modules/express"].Line)
+}
+
+func TestParseYarnLock_Specifiers(t *testing.T) {
+	// Arrange - one entry resolves two specs
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "yarn.lock"), []byte(`# yarn lockfile v1
+
+"@babel/core@^7.0.0", "@babel/core@^7.20.0":
+  version "7.23.0"
+
+lodash@^4.17.21:
+  version "4.17.21"
+`), 0644))
+
+	// Act
+	lockfile, err := ParseYarnLock(filepath.Join(dir, "yarn.lock"))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"@babel/core@^7.0.0":  "7.23.0",
+		"@babel/core@^7.20.0": "7.23.0",
+		"lodash@^4.17.21":     "4.17.21",
+	}, lockfile.Specifiers)
+	assert.Nil(t, lockfile.RootDependencies, "yarn.lock has no root entry")
+	assert.Nil(t, lockfile.Manifest)
+}
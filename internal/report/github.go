@@ -0,0 +1,90 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/scanner"
+)
+
+// GitHubReporter emits GitHub Actions workflow commands (::error / ::warning)
+// The runner turns these into annotations on the pull request's "Files changed" view.
+type GitHubReporter struct {
+	Workspace string // Repository root (GITHUB_WORKSPACE); file paths are made relative to it
+}
+
+// Report implements Reporter
+func (r GitHubReporter) Report(w io.Writer, scans []Scan, meta Meta) error {
+	var b strings.Builder
+
+	for _, scan := range scans {
+		file := r.relativePath(scan.LockfileInfo.Path)
+
+		for _, finding := range sortedFindings(scan.Result.Findings) {
+			pkgID := finding.PackageName + "@" + finding.Version
+
+			props := []string{"file=" + escapeGitHubProperty(file)}
+			if finding.Line > 0 {
+				props = append(props, fmt.Sprintf("line=%d", finding.Line))
+			}
+			props = append(props, "title="+escapeGitHubProperty(findingTitle(finding)))
+
+			message := fmt.Sprintf("%s [%s]: %s\nPath: %s",
+				pkgID, finding.Severity, finding.Reason, strings.Join(finding.Path, " → "))
+			if len(finding.Identifiers) > 0 {
+				message += "\nIDs: " + strings.Join(finding.Identifiers, ", ")
+			}
+			if len(finding.FixedIn) > 0 {
+				message += "\nFixed in: " + strings.Join(finding.FixedIn, ", ")
+			}
+
+			fmt.Fprintf(&b, "::%s %s::%s\n",
+				gitHubCommand(finding.Severity), strings.Join(props, ","), escapeGitHubData(message))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// relativePath makes a lockfile path relative to the workspace, using forward slashes
+func (r GitHubReporter) relativePath(path string) string {
+	if r.Workspace != "" {
+		if abs, err := filepath.Abs(path); err == nil {
+			if rel, err := filepath.Rel(r.Workspace, abs); err == nil && !strings.HasPrefix(rel, "..") {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+// gitHubCommand picks the annotation level for a severity
+func gitHubCommand(severity scanner.Severity) string {
+	switch severity {
+	case scanner.SeverityCritical, scanner.SeverityHigh:
+		return "error"
+	case scanner.SeverityMedium, scanner.SeverityLow:
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+// escapeGitHubData escapes the message part of a workflow command
+func escapeGitHubData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	s = strings.ReplaceAll(s, "\n", "%0A")
+	return s
+}
+
+// escapeGitHubProperty escapes a property value of a workflow command
+func escapeGitHubProperty(s string) string {
+	s = escapeGitHubData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	s = strings.ReplaceAll(s, ",", "%2C")
+	return s
+}
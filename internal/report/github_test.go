@@ -0,0 +1,58 @@
+package report
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubReporter(t *testing.T) {
+	// Arrange - use the testdata directory as the workspace
+	scan := loadTestScan(t, "../../testdata/npm/affected-transitive")
+	workspace, err := filepath.Abs("../../testdata")
+	require.NoError(t, err)
+
+	// Act
+	var buf bytes.Buffer
+	err = GitHubReporter{Workspace: workspace}.Report(&buf, []Scan{scan}, Meta{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t,
+		"::error file=npm/affected-transitive/package-lock.json,line=34,title=Compromised package 02-echo@0.0.7"+
+			"::02-echo@0.0.7 [critical]: Malicious code - Shai Hulud 2.0 attack%0APath: test-affected-transitive → express → 02-echo\n",
+		buf.String())
+}
+
+func TestGitHubReporter_DriftTitle(t *testing.T) {
+	scan := loadTestScan(t, "../../testdata/npm/affected-transitive")
+	scan.Result.Findings = []scanner.Finding{{
+		RuleID:      scanner.RuleRangeMismatch,
+		PackageName: "lodash",
+		Version:     "3.10.1",
+		Severity:    scanner.SeverityHigh,
+		Reason:      "lodash@3.10.1 does not satisfy ^4.17.0 declared in package.json",
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, GitHubReporter{}.Report(&buf, []Scan{scan}, Meta{}))
+
+	assert.Contains(t, buf.String(), "title=Resolved version outside the declared range%3A lodash@3.10.1::")
+}
+
+func TestGitHubCommand(t *testing.T) {
+	assert.Equal(t, "error", gitHubCommand(scanner.SeverityCritical))
+	assert.Equal(t, "error", gitHubCommand(scanner.SeverityHigh))
+	assert.Equal(t, "warning", gitHubCommand(scanner.SeverityMedium))
+	assert.Equal(t, "warning", gitHubCommand(scanner.SeverityLow))
+	assert.Equal(t, "notice", gitHubCommand(scanner.SeverityInfo))
+}
+
+func TestEscapeGitHubProperty(t *testing.T) {
+	assert.Equal(t, "a%3Ab%2Cc%0Ad%25", escapeGitHubProperty("a:b,c\nd%"))
+	assert.Equal(t, "a:b,c%0Ad%25", escapeGitHubData("a:b,c\nd%"))
+}
@@ -0,0 +1,363 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/graph"
+	"github.com/fullstack-spiderman/hulud-scan/internal/parser"
+	"github.com/fullstack-spiderman/hulud-scan/internal/scanner"
+)
+
+// gitLabSchemaVersion is the gl-dependency-scanning-report.json schema we emit
+const gitLabSchemaVersion = "15.0.7"
+
+// gitLabTimeFormat is the timestamp layout required by the GitLab schema
+const gitLabTimeFormat = "2006-01-02T15:04:05"
+
+// GitLab dependency scanning report structure
+type gitLabReport struct {
+	Version         string                 `json:"version"`
+	Scan            gitLabScan             `json:"scan"`
+	Vulnerabilities []gitLabVulnerability  `json:"vulnerabilities"`
+	DependencyFiles []gitLabDependencyFile `json:"dependency_files"`
+}
+
+type gitLabScan struct {
+	Analyzer  gitLabTool `json:"analyzer"`
+	Scanner   gitLabTool `json:"scanner"`
+	Type      string     `json:"type"`
+	StartTime string     `json:"start_time"`
+	EndTime   string     `json:"end_time"`
+	Status    string     `json:"status"`
+}
+
+type gitLabTool struct {
+	ID      string       `json:"id"`
+	Name    string       `json:"name"`
+	URL     string       `json:"url,omitempty"`
+	Version string       `json:"version"`
+	Vendor  gitLabVendor `json:"vendor"`
+}
+
+type gitLabVendor struct {
+	Name string `json:"name"`
+}
+
+type gitLabVulnerability struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Severity    string             `json:"severity"`
+	Solution    string             `json:"solution"`
+	Identifiers []gitLabIdentifier `json:"identifiers"`
+	Links       []gitLabLink       `json:"links,omitempty"`
+	Location    gitLabLocation     `json:"location"`
+}
+
+type gitLabLink struct {
+	URL string `json:"url"`
+}
+
+type gitLabIdentifier struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	URL   string `json:"url,omitempty"`
+}
+
+type gitLabLocation struct {
+	File       string           `json:"file"`
+	Dependency gitLabDependency `json:"dependency"`
+}
+
+type gitLabDependency struct {
+	Package        gitLabPackage         `json:"package"`
+	Version        string                `json:"version"`
+	IID            int                   `json:"iid"`
+	Direct         bool                  `json:"direct"`
+	DependencyPath []gitLabDependencyRef `json:"dependency_path,omitempty"`
+}
+
+type gitLabPackage struct {
+	Name string `json:"name"`
+}
+
+type gitLabDependencyRef struct {
+	IID int `json:"iid"`
+}
+
+type gitLabDependencyFile struct {
+	Path           string             `json:"path"`
+	PackageManager string             `json:"package_manager"`
+	Dependencies   []gitLabDependency `json:"dependencies"`
+}
+
+// GitLabReporter renders scans as a GitLab gl-dependency-scanning-report.json
+type GitLabReporter struct{}
+
+// Report implements Reporter
+func (GitLabReporter) Report(w io.Writer, scans []Scan, meta Meta) error {
+	tool := gitLabTool{
+		ID:      "hulud-scan",
+		Name:    "hulud-scan",
+		URL:     "https://github.com/fullstack-spiderman/hulud-scan",
+		Version: meta.ToolVersion,
+		Vendor:  gitLabVendor{Name: "hulud-scan"},
+	}
+
+	doc := gitLabReport{
+		Version: gitLabSchemaVersion,
+		Scan: gitLabScan{
+			Analyzer:  tool,
+			Scanner:   tool,
+			Type:      "dependency_scanning",
+			StartTime: meta.StartTime.UTC().Format(gitLabTimeFormat),
+			EndTime:   meta.EndTime.UTC().Format(gitLabTimeFormat),
+			Status:    "success",
+		},
+		Vulnerabilities: make([]gitLabVulnerability, 0),
+		DependencyFiles: make([]gitLabDependencyFile, 0, len(scans)),
+	}
+
+	for _, scan := range scans {
+		file := filepath.ToSlash(scan.LockfileInfo.Path)
+		gg := newGitLabGraph(scan.Graph)
+
+		doc.DependencyFiles = append(doc.DependencyFiles, gitLabDependencyFile{
+			Path:           file,
+			PackageManager: gitLabPackageManager(scan.LockfileInfo.Type),
+			Dependencies:   gg.dependencies(),
+		})
+
+		for _, finding := range sortedFindings(scan.Result.Findings) {
+			doc.Vulnerabilities = append(doc.Vulnerabilities, gitLabVulnerabilityFor(file, finding, gg))
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode GitLab report: %w", err)
+	}
+	return nil
+}
+
+// gitLabGraph numbers the packages of one lockfile for GitLab
+// Every lockfile package path gets its own iid, so nested copies of a package
+// at another version stay distinct; numbering in path order keeps iids stable.
+type gitLabGraph struct {
+	graph   *graph.Graph
+	iids    map[*graph.Node]int
+	parents map[*graph.Node]*graph.Node // Shortest-path tree from the root
+}
+
+// newGitLabGraph assigns iids and finds each package's shortest chain from the root
+func newGitLabGraph(g *graph.Graph) *gitLabGraph {
+	paths := make([]string, 0, len(g.Nodes))
+	for path := range g.Nodes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	gg := &gitLabGraph{
+		graph:   g,
+		iids:    make(map[*graph.Node]int, len(paths)),
+		parents: make(map[*graph.Node]*graph.Node, len(paths)),
+	}
+	for i, path := range paths {
+		gg.iids[g.Nodes[path]] = i + 1
+	}
+
+	// Breadth-first from the root, visiting children in iid order so ties break the same way every run
+	visited := map[*graph.Node]bool{g.Root: true}
+	queue := []*graph.Node{g.Root}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		children := append([]*graph.Node(nil), node.Dependencies...)
+		sort.Slice(children, func(i, j int) bool {
+			return gg.iids[children[i]] < gg.iids[children[j]]
+		})
+		for _, child := range children {
+			if !visited[child] {
+				visited[child] = true
+				gg.parents[child] = node
+				queue = append(queue, child)
+			}
+		}
+	}
+	return gg
+}
+
+// node returns the graph node of a lockfile package path, nil if there is none
+func (gg *gitLabGraph) node(path string) *graph.Node {
+	if gg == nil || gg.graph == nil {
+		return nil
+	}
+	return gg.graph.Nodes[path]
+}
+
+// iid returns the iid of a node, 0 for packages the lockfile does not install
+func (gg *gitLabGraph) iid(node *graph.Node) int {
+	if gg == nil || node == nil {
+		return 0
+	}
+	return gg.iids[node]
+}
+
+// dependencies lists every package of the lockfile with its iid
+func (gg *gitLabGraph) dependencies() []gitLabDependency {
+	deps := make([]gitLabDependency, 0, len(gg.iids))
+	for node, iid := range gg.iids {
+		deps = append(deps, gitLabDependency{
+			Package:        gitLabPackage{Name: node.Package.Name},
+			Version:        node.Package.Version,
+			IID:            iid,
+			Direct:         node.IsDirect,
+			DependencyPath: gg.dependencyPath(node),
+		})
+	}
+
+	sort.Slice(deps, func(i, j int) bool {
+		return deps[i].IID < deps[j].IID
+	})
+	return deps
+}
+
+// dependencyPath returns the ancestor iids GitLab expects for a package
+// GitLab wants the chain from a direct dependency down to the immediate parent,
+// so the root project and the package itself are left out.
+func (gg *gitLabGraph) dependencyPath(node *graph.Node) []gitLabDependencyRef {
+	if gg == nil || node == nil {
+		return nil
+	}
+
+	ancestors := make([]gitLabDependencyRef, 0)
+	for parent := gg.parents[node]; parent != nil && parent != gg.graph.Root; parent = gg.parents[parent] {
+		ancestors = append(ancestors, gitLabDependencyRef{IID: gg.iids[parent]})
+	}
+
+	// Collected from the parent upwards; GitLab wants them top-down
+	for i, j := 0, len(ancestors)-1; i < j; i, j = i+1, j-1 {
+		ancestors[i], ancestors[j] = ancestors[j], ancestors[i]
+	}
+	if len(ancestors) == 0 {
+		return nil
+	}
+	return ancestors
+}
+
+// gitLabVulnerabilityFor converts a finding into a GitLab vulnerability
+func gitLabVulnerabilityFor(file string, finding scanner.Finding, gg *gitLabGraph) gitLabVulnerability {
+	pkgID := finding.PackageName + "@" + finding.Version
+
+	identifiers := make([]gitLabIdentifier, 0, len(finding.Identifiers)+1)
+	for _, id := range finding.Identifiers {
+		identifiers = append(identifiers, gitLabIdentifier{
+			Type:  identifierType(id),
+			Name:  id,
+			Value: id,
+			URL:   identifierURL(id),
+		})
+	}
+	// GitLab requires at least one identifier; the package itself is always available
+	identifiers = append(identifiers, gitLabIdentifier{
+		Type:  "hulud-scan",
+		Name:  "hulud-scan " + pkgID,
+		Value: pkgID,
+	})
+
+	links := make([]gitLabLink, 0, len(finding.References))
+	for _, ref := range finding.References {
+		links = append(links, gitLabLink{URL: ref})
+	}
+
+	// IDs are keyed by the package version at its lockfile path, so nested copies
+	// of one version stay distinct; findings of other rules add the rule ID.
+	rule := finding.Rule()
+	key := pkgID
+	if finding.PackagePath != "" {
+		key += "\x00" + finding.PackagePath
+	}
+	description := fmt.Sprintf("%s is listed in the hulud-scan blocklist: %s\nDependency path: %s",
+		pkgID, finding.Reason, strings.Join(finding.Path, " → "))
+	solution := gitLabSolution(pkgID, finding.FixedIn)
+	if rule.ID != scanner.RuleCompromisedPackage {
+		key += "\x00" + rule.ID
+		description = fmt.Sprintf("%s: %s\n%s", rule.Title, finding.Reason, rule.Description)
+		solution = "Regenerate the lockfile with the package manager and review the change that introduced the difference."
+	}
+
+	node := gg.node(finding.PackagePath)
+	return gitLabVulnerability{
+		ID:          gitLabVulnerabilityID(file, key),
+		Name:        finding.Reason,
+		Description: description,
+		Severity:    gitLabSeverity(finding.Severity),
+		Solution:    solution,
+		Identifiers: identifiers,
+		Links:       links,
+		Location: gitLabLocation{
+			File: file,
+			Dependency: gitLabDependency{
+				Package:        gitLabPackage{Name: finding.PackageName},
+				Version:        finding.Version,
+				IID:            gg.iid(node),
+				Direct:         finding.IsDirect,
+				DependencyPath: gg.dependencyPath(node),
+			},
+		},
+	}
+}
+
+// gitLabSolution suggests a fix, naming the fixed version when one is known
+func gitLabSolution(pkgID string, fixedIn []string) string {
+	if len(fixedIn) > 0 {
+		return fmt.Sprintf("Upgrade %s to %s or later.", pkgID, strings.Join(fixedIn, ", "))
+	}
+	return fmt.Sprintf("Remove %s or move to a version that is not blocklisted, then rotate any credentials exposed to the install.", pkgID)
+}
+
+// gitLabVulnerabilityID derives a stable id so GitLab can track the finding across pipelines
+func gitLabVulnerabilityID(file, key string) string {
+	hash := sha256.Sum256([]byte(file + "\x00" + key))
+	return fmt.Sprintf("%x-%x-%x-%x-%x", hash[0:4], hash[4:6], hash[6:8], hash[8:10], hash[10:16])
+}
+
+// gitLabSeverity maps our severities to the capitalized values GitLab accepts
+func gitLabSeverity(severity scanner.Severity) string {
+	switch severity {
+	case scanner.SeverityCritical:
+		return "Critical"
+	case scanner.SeverityHigh:
+		return "High"
+	case scanner.SeverityMedium:
+		return "Medium"
+	case scanner.SeverityLow:
+		return "Low"
+	case scanner.SeverityInfo:
+		return "Info"
+	default:
+		return "Unknown"
+	}
+}
+
+// gitLabPackageManager maps lockfile types to GitLab package manager names
+func gitLabPackageManager(lockType parser.LockfileType) string {
+	switch lockType {
+	case parser.LockfileTypeYarn:
+		return "yarn"
+	case parser.LockfileTypePNPM:
+		return "pnpm"
+	default:
+		// GitLab has no "bun" package manager; bun installs from the npm registry
+		return "npm"
+	}
+}
@@ -0,0 +1,168 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/graph"
+	"github.com/fullstack-spiderman/hulud-scan/internal/parser"
+	"github.com/fullstack-spiderman/hulud-scan/internal/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitLabReporter(t *testing.T) {
+	// Arrange
+	scan := loadTestScan(t, "../../testdata/npm/affected-transitive")
+	start := time.Date(2025, 11, 24, 10, 0, 0, 0, time.UTC)
+	meta := Meta{ToolVersion: "1.2.3", StartTime: start, EndTime: start.Add(2 * time.Second)}
+
+	// Act
+	var buf bytes.Buffer
+	err := GitLabReporter{}.Report(&buf, []Scan{scan}, meta)
+
+	// Assert
+	require.NoError(t, err)
+
+	var doc gitLabReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+
+	assert.Equal(t, gitLabSchemaVersion, doc.Version)
+	assert.Equal(t, "dependency_scanning", doc.Scan.Type)
+	assert.Equal(t, "1.2.3", doc.Scan.Scanner.Version)
+	assert.Equal(t, "2025-11-24T10:00:00", doc.Scan.StartTime)
+	assert.Equal(t, "2025-11-24T10:00:02", doc.Scan.EndTime)
+
+	require.Len(t, doc.Vulnerabilities, 1)
+	vuln := doc.Vulnerabilities[0]
+	assert.Equal(t, "Critical", vuln.Severity)
+	assert.NotEmpty(t, vuln.ID)
+	assert.NotEmpty(t, vuln.Identifiers, "GitLab requires at least one identifier")
+	assert.Equal(t, "../../testdata/npm/affected-transitive/package-lock.json", vuln.Location.File)
+	assert.Equal(t, "02-echo", vuln.Location.Dependency.Package.Name)
+	assert.Equal(t, "0.0.7", vuln.Location.Dependency.Version)
+	assert.False(t, vuln.Location.Dependency.Direct)
+
+	// The dependency path points at express, the direct dependency pulling it in
+	require.Len(t, doc.DependencyFiles, 1)
+	depFile := doc.DependencyFiles[0]
+	assert.Equal(t, "npm", depFile.PackageManager)
+	assert.Len(t, depFile.Dependencies, 4)
+
+	var expressIID int
+	for _, dep := range depFile.Dependencies {
+		if dep.Package.Name == "express" {
+			expressIID = dep.IID
+		}
+	}
+	require.NotZero(t, expressIID)
+	require.Len(t, vuln.Location.Dependency.DependencyPath, 1)
+	assert.Equal(t, expressIID, vuln.Location.Dependency.DependencyPath[0].IID)
+}
+
+// gitLabTestGraph builds the GitLab numbering of a lockfile's packages
+func gitLabTestGraph(t *testing.T, lockfile *parser.Lockfile) *gitLabGraph {
+	t.Helper()
+	g, err := graph.BuildGraph(lockfile)
+	require.NoError(t, err)
+	return newGitLabGraph(g)
+}
+
+func TestGitLabVulnerabilityFor_CVEIdentifier(t *testing.T) {
+	gg := gitLabTestGraph(t, &parser.Lockfile{
+		Name:               "app",
+		DirectDependencies: map[string]string{"lodash": "^4.17.0"},
+		Packages:           map[string]*parser.Package{"node_modules/lodash": {Name: "lodash", Version: "4.17.20"}},
+	})
+	finding := scanner.Finding{
+		PackageName: "lodash",
+		Version:     "4.17.20",
+		PackagePath: "node_modules/lodash",
+		Path:        graph.DependencyPath{"app", "lodash"},
+		Severity:    scanner.SeverityHigh,
+		Reason:      "Prototype pollution",
+		Identifiers: []string{"CVE-2020-8203", "GHSA-p6mc-m468-83gw"},
+		FixedIn:     []string{"4.17.21"},
+		References:  []string{"https://nvd.nist.gov/vuln/detail/CVE-2020-8203"},
+		IsDirect:    true,
+	}
+
+	vuln := gitLabVulnerabilityFor("package-lock.json", finding, gg)
+
+	assert.Equal(t, "High", vuln.Severity)
+	require.Len(t, vuln.Identifiers, 3)
+	assert.Equal(t, "cve", vuln.Identifiers[0].Type)
+	assert.Equal(t, "CVE-2020-8203", vuln.Identifiers[0].Value)
+	assert.Equal(t, "ghsa", vuln.Identifiers[1].Type)
+	assert.Equal(t, "https://github.com/advisories/GHSA-p6mc-m468-83gw", vuln.Identifiers[1].URL)
+	assert.Contains(t, vuln.Solution, "4.17.21")
+	assert.Equal(t, []gitLabLink{{URL: "https://nvd.nist.gov/vuln/detail/CVE-2020-8203"}}, vuln.Links)
+	assert.Equal(t, 1, vuln.Location.Dependency.IID)
+	assert.Empty(t, vuln.Location.Dependency.DependencyPath, "direct dependencies have no ancestors")
+}
+
+func TestGitLabGraph_NestedCopies(t *testing.T) {
+	// Arrange - express pulls in debug 2.x next to the project's own debug 4.x
+	gg := gitLabTestGraph(t, &parser.Lockfile{
+		Name:               "app",
+		DirectDependencies: map[string]string{"express": "^4.0.0", "debug": "^4.0.0"},
+		Packages: map[string]*parser.Package{
+			"node_modules/express":                    {Name: "express", Version: "4.18.2", Dependencies: map[string]string{"debug": "2.6.9"}},
+			"node_modules/debug":                      {Name: "debug", Version: "4.3.4"},
+			"node_modules/express/node_modules/debug": {Name: "debug", Version: "2.6.9"},
+		},
+	})
+	finding := scanner.Finding{
+		PackageName: "debug",
+		Version:     "2.6.9",
+		PackagePath: "node_modules/express/node_modules/debug",
+		Severity:    scanner.SeverityCritical,
+	}
+
+	// Act
+	deps := gg.dependencies()
+	vuln := gitLabVulnerabilityFor("package-lock.json", finding, gg)
+
+	// Assert - iids follow the sorted package paths and are unique
+	require.Len(t, deps, 3)
+	assert.Equal(t, gitLabDependency{Package: gitLabPackage{Name: "debug"}, Version: "4.3.4", IID: 1, Direct: true}, deps[0])
+	assert.Equal(t, gitLabPackage{Name: "express"}, deps[1].Package)
+	assert.Equal(t, 2, deps[1].IID)
+	assert.Equal(t, "2.6.9", deps[2].Version)
+	assert.Equal(t, 3, deps[2].IID)
+	assert.Equal(t, 3, vuln.Location.Dependency.IID, "the finding points at the nested copy")
+
+	other := finding
+	other.PackagePath = "node_modules/other/node_modules/debug"
+	assert.NotEqual(t, vuln.ID, gitLabVulnerabilityFor("package-lock.json", other, gg).ID,
+		"two installs of one version get distinct IDs")
+}
+
+func TestGitLabVulnerabilityFor_RuleID(t *testing.T) {
+	// Arrange - the same package version flagged by the blocklist and by the drift check
+	compromised := scanner.Finding{PackageName: "evil", Version: "1.0.0", Severity: scanner.SeverityCritical}
+	drift := compromised
+	drift.RuleID = scanner.RuleUndeclaredDependency
+	drift.Severity = scanner.SeverityHigh
+
+	// Act
+	a := gitLabVulnerabilityFor("package-lock.json", compromised, nil)
+	b := gitLabVulnerabilityFor("package-lock.json", drift, nil)
+
+	// Assert
+	assert.Equal(t, gitLabVulnerabilityID("package-lock.json", "evil@1.0.0"), a.ID)
+	assert.NotEqual(t, a.ID, b.ID)
+	assert.Contains(t, b.Description, "Lockfile dependency not in package.json")
+}
+
+func TestGitLabReporter_Clean(t *testing.T) {
+	scan := loadTestScan(t, "../../testdata/npm/clean")
+
+	var buf bytes.Buffer
+	require.NoError(t, GitLabReporter{}.Report(&buf, []Scan{scan}, Meta{}))
+
+	// vulnerabilities must be an empty array, not null
+	assert.Contains(t, buf.String(), `"vulnerabilities": []`)
+}
@@ -0,0 +1,160 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/graph"
+	"github.com/fullstack-spiderman/hulud-scan/internal/scanner"
+)
+
+// JSON report structure
+// Field names are part of the public output and should only ever be added to.
+type jsonReport struct {
+	Tool      jsonTool       `json:"tool"`
+	StartTime time.Time      `json:"start_time"`
+	EndTime   time.Time      `json:"end_time"`
+	Lockfiles []jsonLockfile `json:"lockfiles"`
+}
+
+type jsonTool struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type jsonLockfile struct {
+	Path           string        `json:"path"`
+	Type           string        `json:"type"`
+	Project        string        `json:"project"`
+	ProjectVersion string        `json:"project_version"`
+	TotalPackages  int           `json:"total_packages"`
+	IssuesFound    int           `json:"issues_found"`
+	Findings       []jsonFinding `json:"findings"`
+}
+
+type jsonFinding struct {
+	Rule           string       `json:"rule"`
+	Package        string       `json:"package"`
+	Version        string       `json:"version"`
+	Severity       string       `json:"severity"`
+	Reason         string       `json:"reason"`
+	CVE            string       `json:"cve,omitempty"`
+	Identifiers    []string     `json:"identifiers,omitempty"`
+	CVSSScore      float64      `json:"cvss_score,omitempty"`
+	FixedIn        []string     `json:"fixed_in,omitempty"`
+	References     []string     `json:"references,omitempty"`
+	Tags           []string     `json:"tags,omitempty"`
+	Direct         bool         `json:"direct"`
+	Path           []string     `json:"path"`
+	Paths          [][]string   `json:"paths,omitempty"`
+	PathsTruncated bool         `json:"paths_truncated,omitempty"`
+	EntryPoints    []string     `json:"entry_points,omitempty"`
+	Sources        []string     `json:"sources,omitempty"`
+	Metrics        *jsonMetrics `json:"metrics,omitempty"`
+}
+
+// jsonMetrics is the blast radius of a flagged package in the dependency graph
+type jsonMetrics struct {
+	Dependents     int      `json:"dependents"`
+	EntryPoints    int      `json:"entry_points"`
+	FanOut         int      `json:"fan_out"`
+	DominatorDepth int      `json:"dominator_depth"`
+	Dominators     []string `json:"dominators"`
+	Dominates      int      `json:"dominates"`
+}
+
+// JSONReporter renders scan results as a JSON document for archival and tooling
+type JSONReporter struct{}
+
+// Report implements Reporter
+func (JSONReporter) Report(w io.Writer, scans []Scan, meta Meta) error {
+	doc := jsonReport{
+		Tool:      jsonTool{Name: "hulud-scan", Version: meta.ToolVersion},
+		StartTime: meta.StartTime.UTC(),
+		EndTime:   meta.EndTime.UTC(),
+		Lockfiles: make([]jsonLockfile, 0, len(scans)),
+	}
+
+	for _, scan := range scans {
+		lockfile := jsonLockfile{
+			Path:           scan.LockfileInfo.Path,
+			Type:           string(scan.LockfileInfo.Type),
+			Project:        scan.Lockfile.Name,
+			ProjectVersion: scan.Lockfile.Version,
+			TotalPackages:  scan.Result.TotalPackages,
+			IssuesFound:    scan.Result.IssuesFound,
+			Findings:       make([]jsonFinding, 0, len(scan.Result.Findings)),
+		}
+
+		var metrics map[*graph.Node]*graph.Metrics
+		if scan.Graph != nil && len(scan.Result.Findings) > 0 {
+			metrics = scan.Graph.MetricsFor(flaggedNodes(scan.Graph, scan.Result.Findings))
+		}
+
+		for _, finding := range sortedFindings(scan.Result.Findings) {
+			lockfile.Findings = append(lockfile.Findings, jsonFinding{
+				Rule:           finding.Rule().ID,
+				Package:        finding.PackageName,
+				Version:        finding.Version,
+				Severity:       string(finding.Severity),
+				Reason:         finding.Reason,
+				CVE:            finding.CVE(),
+				Identifiers:    finding.Identifiers,
+				CVSSScore:      finding.CVSSScore,
+				FixedIn:        finding.FixedIn,
+				References:     finding.References,
+				Tags:           finding.Tags,
+				Direct:         finding.IsDirect,
+				Path:           finding.Path,
+				Paths:          pathStrings(finding.Paths),
+				PathsTruncated: finding.PathsTruncated,
+				EntryPoints:    finding.EntryPoints,
+				Sources:        finding.Sources,
+				Metrics:        findingMetrics(scan.Graph, metrics, finding.PackagePath),
+			})
+		}
+
+		doc.Lockfiles = append(doc.Lockfiles, lockfile)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode JSON report: %w", err)
+	}
+	return nil
+}
+
+// flaggedNodes returns the graph nodes of the packages findings point at
+func flaggedNodes(g *graph.Graph, findings []scanner.Finding) []*graph.Node {
+	nodes := make([]*graph.Node, 0, len(findings))
+	for _, finding := range findings {
+		if node := g.Nodes[finding.PackagePath]; node != nil {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// findingMetrics looks up the graph metrics of a flagged package
+func findingMetrics(g *graph.Graph, metrics map[*graph.Node]*graph.Metrics, path string) *jsonMetrics {
+	if g == nil || g.Nodes[path] == nil {
+		return nil
+	}
+	m := metrics[g.Nodes[path]]
+
+	dominators := make([]string, 0, len(m.Dominators))
+	for _, node := range m.Dominators {
+		dominators = append(dominators, node.Package.Name)
+	}
+	return &jsonMetrics{
+		Dependents:     m.Dependents,
+		EntryPoints:    m.EntryPoints,
+		FanOut:         m.FanOut,
+		DominatorDepth: m.DominatorDepth,
+		Dominators:     dominators,
+		Dominates:      m.Dominates,
+	}
+}
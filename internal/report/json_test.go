@@ -0,0 +1,42 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONReporter(t *testing.T) {
+	// Arrange
+	scan := loadTestScan(t, "../../testdata/npm/affected-transitive")
+
+	// Act
+	var buf bytes.Buffer
+	err := JSONReporter{}.Report(&buf, []Scan{scan}, Meta{ToolVersion: "1.0.0"})
+
+	// Assert
+	require.NoError(t, err)
+
+	var doc jsonReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+
+	assert.Equal(t, "1.0.0", doc.Tool.Version)
+	require.Len(t, doc.Lockfiles, 1)
+
+	lockfile := doc.Lockfiles[0]
+	assert.Equal(t, "npm", lockfile.Type)
+	assert.Equal(t, 4, lockfile.TotalPackages)
+	assert.Equal(t, 1, lockfile.IssuesFound)
+	require.Len(t, lockfile.Findings, 1)
+	assert.Equal(t, "compromised-package", lockfile.Findings[0].Rule)
+	assert.Equal(t, "02-echo", lockfile.Findings[0].Package)
+	assert.Equal(t, []string{"test-affected-transitive", "express", "02-echo"}, lockfile.Findings[0].Path)
+	assert.Equal(t, [][]string{{"test-affected-transitive", "express", "02-echo"}}, lockfile.Findings[0].Paths)
+	assert.Equal(t, []string{"express"}, lockfile.Findings[0].EntryPoints)
+	assert.Equal(t, &jsonMetrics{Dependents: 1, EntryPoints: 1, DominatorDepth: 1, Dominators: []string{"express"}},
+		lockfile.Findings[0].Metrics)
+	assert.Empty(t, lockfile.Findings[0].Sources, "LoadBlocklist does not tag sources")
+}
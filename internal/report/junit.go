@@ -0,0 +1,165 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/scanner"
+)
+
+// JUnit XML structure understood by Jenkins, GitLab and most CI dashboards
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Cases      []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",cdata"`
+}
+
+// JUnitReporter renders scans as JUnit XML
+// Each lockfile becomes a testsuite and each finding a failing testcase.
+type JUnitReporter struct {
+	IncludePassing bool // Also list every clean package as a passing testcase
+}
+
+// Report implements Reporter
+func (r JUnitReporter) Report(w io.Writer, scans []Scan, meta Meta) error {
+	doc := junitTestSuites{Name: "hulud-scan"}
+
+	for _, scan := range scans {
+		suite := buildJUnitSuite(scan, r.IncludePassing)
+		doc.Tests += suite.Tests
+		doc.Failures += suite.Failures
+		doc.Suites = append(doc.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode JUnit XML: %w", err)
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// buildJUnitSuite converts a single scan into a testsuite
+func buildJUnitSuite(scan Scan, includePassing bool) junitTestSuite {
+	suiteName := scan.LockfileInfo.Path
+	className := scan.LockfileInfo.Filename
+
+	suite := junitTestSuite{
+		Name: suiteName,
+		Properties: []junitProperty{
+			{Name: "lockfile.type", Value: string(scan.LockfileInfo.Type)},
+			{Name: "project", Value: scan.Lockfile.Name + "@" + scan.Lockfile.Version},
+		},
+	}
+
+	// Failing cases: one per finding
+	flagged := make(map[string]bool)
+	for _, finding := range sortedFindings(scan.Result.Findings) {
+		id := finding.PackageName + "@" + finding.Version
+		flagged[id] = true
+
+		suite.Cases = append(suite.Cases, junitTestCase{
+			Name:      id,
+			ClassName: className,
+			Failure: &junitFailure{
+				Message: finding.Reason,
+				Type:    string(finding.Severity),
+				Body:    junitFailureBody(finding),
+			},
+		})
+		suite.Failures++
+	}
+
+	// Passing cases: every package that was not flagged
+	if includePassing && scan.Graph != nil {
+		passing := make([]string, 0, len(scan.Graph.Nodes))
+		seen := make(map[string]bool)
+		for _, node := range scan.Graph.Nodes {
+			id := node.Package.Name + "@" + node.Package.Version
+			if flagged[id] || seen[id] {
+				continue
+			}
+			seen[id] = true
+			passing = append(passing, id)
+		}
+		sort.Strings(passing)
+
+		for _, id := range passing {
+			suite.Cases = append(suite.Cases, junitTestCase{
+				Name:      id,
+				ClassName: className,
+			})
+		}
+	}
+
+	suite.Tests = len(suite.Cases)
+	return suite
+}
+
+// junitFailureBody describes the finding in the testcase body
+func junitFailureBody(finding scanner.Finding) string {
+	dependencyType := "transitive"
+	if finding.IsDirect {
+		dependencyType = "direct"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Rule: %s\n", finding.Rule().ID)
+	fmt.Fprintf(&b, "Severity: %s\n", finding.Severity)
+	fmt.Fprintf(&b, "Type: %s dependency\n", dependencyType)
+	fmt.Fprintf(&b, "Path: %s\n", strings.Join(finding.Path, " → "))
+	if len(finding.Identifiers) > 0 {
+		fmt.Fprintf(&b, "IDs: %s\n", strings.Join(finding.Identifiers, ", "))
+	}
+	if len(finding.FixedIn) > 0 {
+		fmt.Fprintf(&b, "Fixed in: %s\n", strings.Join(finding.FixedIn, ", "))
+	}
+	if len(finding.Tags) > 0 {
+		fmt.Fprintf(&b, "Tags: %s\n", strings.Join(finding.Tags, ", "))
+	}
+	for _, ref := range finding.References {
+		fmt.Fprintf(&b, "Link: %s\n", ref)
+	}
+	if len(finding.Sources) > 0 {
+		fmt.Fprintf(&b, "Source: %s\n", strings.Join(finding.Sources, ", "))
+	}
+	return b.String()
+}
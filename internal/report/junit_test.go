@@ -0,0 +1,101 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/graph"
+	"github.com/fullstack-spiderman/hulud-scan/internal/parser"
+	"github.com/fullstack-spiderman/hulud-scan/internal/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadTestScan scans a testdata project against the sample blocklist
+func loadTestScan(t *testing.T, projectPath string) Scan {
+	t.Helper()
+
+	lockfile, info, err := parser.ParseAuto(projectPath)
+	require.NoError(t, err)
+
+	g, err := graph.BuildGraph(lockfile)
+	require.NoError(t, err)
+
+	blocklist, err := scanner.LoadBlocklist("../../testdata/sample-blocklist.csv")
+	require.NoError(t, err)
+
+	return Scan{
+		Result:       scanner.ScanGraph(g, blocklist),
+		Lockfile:     lockfile,
+		LockfileInfo: info,
+		Graph:        g,
+	}
+}
+
+func TestJUnitReporter_Findings(t *testing.T) {
+	// Arrange
+	scan := loadTestScan(t, "../../testdata/npm/affected-transitive")
+
+	// Act
+	var buf bytes.Buffer
+	err := JUnitReporter{}.Report(&buf, []Scan{scan}, Meta{})
+
+	// Assert
+	require.NoError(t, err)
+
+	var doc junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+
+	assert.Equal(t, 1, doc.Tests)
+	assert.Equal(t, 1, doc.Failures)
+	require.Len(t, doc.Suites, 1)
+
+	suite := doc.Suites[0]
+	assert.Contains(t, suite.Name, "package-lock.json")
+	require.Len(t, suite.Cases, 1)
+
+	testCase := suite.Cases[0]
+	assert.Equal(t, "02-echo@0.0.7", testCase.Name)
+	assert.Equal(t, "package-lock.json", testCase.ClassName)
+	require.NotNil(t, testCase.Failure)
+	assert.Equal(t, "critical", testCase.Failure.Type)
+	assert.Contains(t, testCase.Failure.Message, "Shai Hulud")
+	assert.Contains(t, testCase.Failure.Body, "test-affected-transitive → express → 02-echo")
+}
+
+func TestJUnitReporter_IncludePassing(t *testing.T) {
+	scan := loadTestScan(t, "../../testdata/npm/affected-transitive")
+
+	var buf bytes.Buffer
+	require.NoError(t, JUnitReporter{IncludePassing: true}.Report(&buf, []Scan{scan}, Meta{}))
+
+	var doc junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+
+	// 4 packages: 1 failing, 3 passing
+	assert.Equal(t, 4, doc.Tests)
+	assert.Equal(t, 1, doc.Failures)
+
+	passing := 0
+	for _, testCase := range doc.Suites[0].Cases {
+		if testCase.Failure == nil {
+			passing++
+		}
+	}
+	assert.Equal(t, 3, passing)
+}
+
+func TestJUnitReporter_Clean(t *testing.T) {
+	scan := loadTestScan(t, "../../testdata/npm/clean")
+
+	var buf bytes.Buffer
+	require.NoError(t, JUnitReporter{}.Report(&buf, []Scan{scan}, Meta{}))
+
+	var doc junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+
+	assert.Equal(t, 0, doc.Failures)
+	require.Len(t, doc.Suites, 1)
+	assert.Empty(t, doc.Suites[0].Cases)
+}
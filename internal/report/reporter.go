@@ -0,0 +1,58 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Reporter renders scan results in one output format
+// Every output format implements this so a single scan can feed several outputs.
+type Reporter interface {
+	Report(w io.Writer, scans []Scan, meta Meta) error
+}
+
+// Options configures the reporters returned by New
+type Options struct {
+	IncludePassing bool   // JUnit: list clean packages as passing testcases
+	TemplatePath   string // Template: path to the text/template file
+	Workspace      string // GitHub: repository root that annotation paths are relative to
+}
+
+// factories maps format names to reporter constructors
+var factories = map[string]func(Options) (Reporter, error){
+	"table":  func(Options) (Reporter, error) { return TableReporter{}, nil },
+	"json":   func(Options) (Reporter, error) { return JSONReporter{}, nil },
+	"sarif":  func(Options) (Reporter, error) { return SARIFReporter{}, nil },
+	"junit":  func(opts Options) (Reporter, error) { return JUnitReporter{IncludePassing: opts.IncludePassing}, nil },
+	"gitlab": func(Options) (Reporter, error) { return GitLabReporter{}, nil },
+	"github": func(opts Options) (Reporter, error) { return GitHubReporter{Workspace: opts.Workspace}, nil },
+	"template": func(opts Options) (Reporter, error) {
+		return NewTemplateReporter(opts.TemplatePath)
+	},
+}
+
+// New returns the reporter for a format name
+func New(format string, opts Options) (Reporter, error) {
+	factory, exists := factories[strings.ToLower(format)]
+	if !exists {
+		return nil, fmt.Errorf("unknown output format %q (supported: %s)", format, strings.Join(Formats(), ", "))
+	}
+	return factory(opts)
+}
+
+// Formats returns the names of all supported output formats
+func Formats() []string {
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsMachineReadable reports whether a format must own stdout exclusively
+func IsMachineReadable(format string) bool {
+	return strings.ToLower(format) != "table"
+}
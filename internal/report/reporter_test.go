@@ -0,0 +1,52 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testOptions lets every format be constructed, including template
+var testOptions = Options{TemplatePath: "../../testdata/templates/slack.tmpl"}
+
+func TestNew(t *testing.T) {
+	for _, format := range Formats() {
+		t.Run(format, func(t *testing.T) {
+			reporter, err := New(format, testOptions)
+			require.NoError(t, err)
+			require.NotNil(t, reporter)
+		})
+	}
+}
+
+func TestNew_UnknownFormat(t *testing.T) {
+	_, err := New("html", Options{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown output format")
+	assert.Contains(t, err.Error(), "sarif")
+}
+
+func TestReporters_RenderSameScan(t *testing.T) {
+	// One scan should feed every reporter without being re-run
+	scan := loadTestScan(t, "../../testdata/npm/affected-transitive")
+
+	for _, format := range Formats() {
+		t.Run(format, func(t *testing.T) {
+			reporter, err := New(format, testOptions)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, reporter.Report(&buf, []Scan{scan}, Meta{ToolVersion: "test"}))
+			assert.Contains(t, buf.String(), "02-echo")
+		})
+	}
+}
+
+func TestIsMachineReadable(t *testing.T) {
+	assert.False(t, IsMachineReadable("table"))
+	assert.True(t, IsMachineReadable("json"))
+	assert.True(t, IsMachineReadable("sarif"))
+}
@@ -0,0 +1,192 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/scanner"
+)
+
+// sarifRuleID namespaces a scanner rule ID in SARIF output
+func sarifRuleID(id string) string {
+	return "hulud-scan/" + id
+}
+
+// SARIF 2.1.0 structure (only the parts GitHub code scanning uses)
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	FullDescription      sarifMessage       `json:"fullDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	Properties           map[string]string  `json:"properties,omitempty"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Properties          map[string]any    `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFReporter renders scan results as SARIF 2.1.0 for code scanning tools
+type SARIFReporter struct{}
+
+// Report implements Reporter
+func (SARIFReporter) Report(w io.Writer, scans []Scan, meta Meta) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "hulud-scan",
+			Version:        meta.ToolVersion,
+			InformationURI: "https://github.com/fullstack-spiderman/hulud-scan",
+			Rules:          make([]sarifRule, 0, len(scanner.Rules)),
+		}},
+		Results: make([]sarifResult, 0),
+	}
+
+	for _, rule := range scanner.Rules {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:                   sarifRuleID(rule.ID),
+			Name:                 rule.Name,
+			ShortDescription:     sarifMessage{Text: rule.Title},
+			FullDescription:      sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(rule.Severity)},
+			Properties:           map[string]string{"security-severity": sarifSecuritySeverity(rule.Severity)},
+		})
+	}
+
+	for _, scan := range scans {
+		uri := filepath.ToSlash(scan.LockfileInfo.Path)
+
+		for _, finding := range sortedFindings(scan.Result.Findings) {
+			pkgID := finding.PackageName + "@" + finding.Version
+			text := fmt.Sprintf("%s: %s (path: %s)", pkgID, finding.Reason, strings.Join(finding.Path, " → "))
+
+			properties := map[string]any{
+				"severity": string(finding.Severity),
+				"direct":   finding.IsDirect,
+				"path":     finding.Path,
+			}
+			if len(finding.Identifiers) > 0 {
+				properties["identifiers"] = finding.Identifiers
+			}
+			if len(finding.FixedIn) > 0 {
+				properties["fixedIn"] = finding.FixedIn
+			}
+			if len(finding.References) > 0 {
+				properties["references"] = finding.References
+			}
+			if len(finding.Tags) > 0 {
+				properties["tags"] = finding.Tags
+			}
+			if len(finding.Sources) > 0 {
+				properties["sources"] = finding.Sources
+			}
+
+			location := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: uri}}
+			if finding.Line > 0 {
+				location.Region = &sarifRegion{StartLine: finding.Line}
+			}
+
+			run.Results = append(run.Results, sarifResult{
+				RuleID:              sarifRuleID(finding.Rule().ID),
+				Level:               sarifLevel(finding.Severity),
+				Message:             sarifMessage{Text: text},
+				Locations:           []sarifLocation{{PhysicalLocation: location}},
+				PartialFingerprints: map[string]string{"packageVersion/v1": pkgID},
+				Properties:          properties,
+			})
+		}
+	}
+
+	doc := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode SARIF report: %w", err)
+	}
+	return nil
+}
+
+// sarifLevel maps our severities to SARIF result levels
+func sarifLevel(severity scanner.Severity) string {
+	switch severity {
+	case scanner.SeverityCritical, scanner.SeverityHigh:
+		return "error"
+	case scanner.SeverityMedium:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// sarifSecuritySeverity maps our severities to the CVSS-like score GitHub uses to rank alerts
+func sarifSecuritySeverity(severity scanner.Severity) string {
+	switch severity {
+	case scanner.SeverityCritical:
+		return "9.8"
+	case scanner.SeverityHigh:
+		return "7.5"
+	case scanner.SeverityMedium:
+		return "5.0"
+	default:
+		return "2.0"
+	}
+}
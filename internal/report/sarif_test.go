@@ -0,0 +1,84 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSARIFReporter(t *testing.T) {
+	// Arrange
+	scan := loadTestScan(t, "../../testdata/npm/affected-transitive")
+
+	// Act
+	var buf bytes.Buffer
+	err := SARIFReporter{}.Report(&buf, []Scan{scan}, Meta{ToolVersion: "1.0.0"})
+
+	// Assert
+	require.NoError(t, err)
+
+	var doc sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+
+	assert.Equal(t, "2.1.0", doc.Version)
+	require.Len(t, doc.Runs, 1)
+	assert.Equal(t, "hulud-scan", doc.Runs[0].Tool.Driver.Name)
+
+	require.Len(t, doc.Runs[0].Results, 1)
+	result := doc.Runs[0].Results[0]
+	assert.Equal(t, "hulud-scan/compromised-package", result.RuleID)
+	assert.Equal(t, "error", result.Level)
+	assert.Equal(t, "02-echo@0.0.7", result.PartialFingerprints["packageVersion/v1"])
+	assert.Contains(t, result.Locations[0].PhysicalLocation.ArtifactLocation.URI, "package-lock.json")
+}
+
+func TestSARIFReporter_Rules(t *testing.T) {
+	// Arrange - a drift finding next to the blocklist match
+	scan := loadTestScan(t, "../../testdata/npm/affected-transitive")
+	scan.Result.Findings = append(scan.Result.Findings, scanner.Finding{
+		RuleID:      scanner.RuleUndeclaredDependency,
+		PackageName: "evil",
+		Version:     "1.0.0",
+		Severity:    scanner.SeverityHigh,
+	})
+
+	// Act
+	var buf bytes.Buffer
+	require.NoError(t, SARIFReporter{}.Report(&buf, []Scan{scan}, Meta{ToolVersion: "1.0.0"}))
+
+	// Assert
+	var doc sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+
+	rules := doc.Runs[0].Tool.Driver.Rules
+	require.Len(t, rules, len(scanner.Rules))
+	assert.Equal(t, "hulud-scan/lockfile-undeclared-dependency", rules[2].ID)
+	assert.Equal(t, "7.5", rules[2].Properties["security-severity"])
+
+	require.Len(t, doc.Runs[0].Results, 2)
+	assert.Equal(t, "hulud-scan/compromised-package", doc.Runs[0].Results[0].RuleID)
+	assert.Equal(t, "hulud-scan/lockfile-undeclared-dependency", doc.Runs[0].Results[1].RuleID)
+}
+
+func TestSARIFLevel(t *testing.T) {
+	tests := []struct {
+		severity scanner.Severity
+		expected string
+	}{
+		{scanner.SeverityCritical, "error"},
+		{scanner.SeverityHigh, "error"},
+		{scanner.SeverityMedium, "warning"},
+		{scanner.SeverityLow, "note"},
+		{scanner.SeverityInfo, "note"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.severity), func(t *testing.T) {
+			assert.Equal(t, tt.expected, sarifLevel(tt.severity))
+		})
+	}
+}
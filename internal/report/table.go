@@ -0,0 +1,103 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/scanner"
+)
+
+// TableReporter renders scan results in a human-readable layout
+type TableReporter struct{}
+
+// Report implements Reporter
+func (TableReporter) Report(w io.Writer, scans []Scan, meta Meta) error {
+	for _, scan := range scans {
+		if err := writeTable(w, scan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTable displays the results of a single lockfile
+func writeTable(w io.Writer, scan Scan) error {
+	result := scan.Result
+
+	var b strings.Builder
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, strings.Repeat("=", 60))
+	fmt.Fprintln(&b, "SCAN RESULTS")
+	fmt.Fprintln(&b, strings.Repeat("=", 60))
+	fmt.Fprintln(&b)
+
+	fmt.Fprintf(&b, "Total packages scanned: %d\n", result.TotalPackages)
+	fmt.Fprintf(&b, "Issues found: %d\n\n", result.IssuesFound)
+
+	if result.IssuesFound == 0 {
+		fmt.Fprintln(&b, "✅ No compromised packages detected!")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	// Display findings
+	fmt.Fprintf(&b, "⚠️  SECURITY ISSUES DETECTED:\n\n")
+
+	for i, finding := range sortedFindings(result.Findings) {
+		fmt.Fprintf(&b, "%d. %s@%s [%s]\n", i+1, finding.PackageName, finding.Version, strings.ToUpper(string(finding.Severity)))
+		if rule := finding.Rule(); rule.ID != scanner.RuleCompromisedPackage {
+			fmt.Fprintf(&b, "   Rule: %s (%s)\n", rule.ID, rule.Title)
+		}
+
+		// Show dependency path
+		pathStr := strings.Join(finding.Path, " → ")
+		dependencyType := "transitive"
+		if finding.IsDirect {
+			dependencyType = "direct"
+		}
+		fmt.Fprintf(&b, "   Type: %s dependency\n", dependencyType)
+		fmt.Fprintf(&b, "   Path: %s\n", pathStr)
+		if len(finding.Paths) > 1 {
+			fmt.Fprintf(&b, "   Paths: %s, through direct dependencies: %s\n", pathCount(finding), strings.Join(finding.EntryPoints, ", "))
+		}
+		fmt.Fprintf(&b, "   Reason: %s\n", finding.Reason)
+
+		if len(finding.Identifiers) > 0 {
+			fmt.Fprintf(&b, "   IDs: %s\n", strings.Join(finding.Identifiers, ", "))
+		}
+
+		if finding.CVSSScore > 0 {
+			fmt.Fprintf(&b, "   CVSS: %.1f\n", finding.CVSSScore)
+		}
+
+		if len(finding.FixedIn) > 0 {
+			fmt.Fprintf(&b, "   Fixed in: %s\n", strings.Join(finding.FixedIn, ", "))
+		}
+
+		if len(finding.Tags) > 0 {
+			fmt.Fprintf(&b, "   Tags: %s\n", strings.Join(finding.Tags, ", "))
+		}
+
+		for _, ref := range finding.References {
+			fmt.Fprintf(&b, "   Link: %s\n", ref)
+		}
+
+		if len(finding.Sources) > 0 {
+			fmt.Fprintf(&b, "   Source: %s\n", strings.Join(finding.Sources, ", "))
+		}
+
+		fmt.Fprintln(&b)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// pathCount formats how many chains lead to a finding, marking a capped count
+func pathCount(finding scanner.Finding) string {
+	if finding.PathsTruncated {
+		return fmt.Sprintf("%d+", len(finding.Paths))
+	}
+	return fmt.Sprintf("%d", len(finding.Paths))
+}
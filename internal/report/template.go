@@ -0,0 +1,144 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// TemplateReporter renders scan results through a user-supplied text/template
+// The template receives a View; see view.go for the available fields.
+type TemplateReporter struct {
+	tmpl *template.Template
+}
+
+// NewTemplateReporter parses the template file at path
+func NewTemplateReporter(path string) (*TemplateReporter, error) {
+	if path == "" {
+		return nil, fmt.Errorf("template format requires --template <file>")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+
+	tmpl, err := template.New(filepath.Base(path)).Funcs(TemplateFuncs()).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", path, err)
+	}
+
+	return &TemplateReporter{tmpl: tmpl}, nil
+}
+
+// Report implements Reporter
+func (r *TemplateReporter) Report(w io.Writer, scans []Scan, meta Meta) error {
+	if err := r.tmpl.Execute(w, NewView(scans, meta)); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	return nil
+}
+
+// TemplateFuncs returns the helper functions available to user templates
+//
+//	join        join a list with a separator:      {{join .Path " > "}}
+//	path        render a dependency chain:         {{path .Path}}
+//	upper/lower change case:                       {{upper .Severity}}
+//	severityColor hex color for a severity:        {{severityColor .Severity}}
+//	severityEmoji emoji for a severity:            {{severityEmoji .Severity}}
+//	countSeverity findings of a severity:          {{countSeverity .Findings "critical"}}
+//	direct      only direct findings:              {{range direct .Findings}}
+//	csv         quote values as one CSV record:    {{csv .Package .Version .Reason}}
+//	add         integer addition:                  {{add $i 1}}
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"join": func(items []string, sep string) string {
+			return strings.Join(items, sep)
+		},
+		"path": func(items []string) string {
+			return strings.Join(items, " → ")
+		},
+		"upper":         strings.ToUpper,
+		"lower":         strings.ToLower,
+		"severityColor": severityColor,
+		"severityEmoji": severityEmoji,
+		"countSeverity": countSeverity,
+		"direct":        directFindings,
+		"csv":           csvRecord,
+		"add": func(a, b int) int {
+			return a + b
+		},
+	}
+}
+
+// severityColor returns a hex color suitable for chat attachments and HTML
+func severityColor(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return "#b71c1c"
+	case "high":
+		return "#e65100"
+	case "medium":
+		return "#f9a825"
+	case "low":
+		return "#1565c0"
+	default:
+		return "#757575"
+	}
+}
+
+// severityEmoji returns an emoji matching the console output style
+func severityEmoji(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return "🔴"
+	case "high":
+		return "🟠"
+	case "medium":
+		return "🟡"
+	case "low":
+		return "🔵"
+	default:
+		return "⚪"
+	}
+}
+
+// countSeverity counts findings with the given severity
+func countSeverity(findings []FindingView, severity string) int {
+	count := 0
+	for _, finding := range findings {
+		if strings.EqualFold(finding.Severity, severity) {
+			count++
+		}
+	}
+	return count
+}
+
+// directFindings keeps only findings for direct dependencies
+func directFindings(findings []FindingView) []FindingView {
+	direct := make([]FindingView, 0, len(findings))
+	for _, finding := range findings {
+		if finding.Direct {
+			direct = append(direct, finding)
+		}
+	}
+	return direct
+}
+
+// csvRecord quotes values as a single CSV line (without the trailing newline)
+func csvRecord(values ...string) (string, error) {
+	var b strings.Builder
+	writer := csv.NewWriter(&b)
+	if err := writer.Write(values); err != nil {
+		return "", err
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
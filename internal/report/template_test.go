@@ -0,0 +1,90 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateReporter(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{
+			name:     "slack message",
+			template: "../../testdata/templates/slack.tmpl",
+			expected: "*hulud-scan* test-affected-transitive (package-lock.json): 1 issue(s), 1 critical\n" +
+				"1. 🔴 `02-echo@0.0.7` CRITICAL via test-affected-transitive → express → 02-echo\n",
+		},
+		{
+			name:     "custom csv",
+			template: "../../testdata/templates/findings.csv.tmpl",
+			expected: "lockfile,package,version,severity,path\n" +
+				"package-lock.json,02-echo,0.0.7,critical,test-affected-transitive > express > 02-echo\n",
+		},
+	}
+
+	scan := loadTestScan(t, "../../testdata/npm/affected-transitive")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter, err := NewTemplateReporter(tt.template)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, reporter.Report(&buf, []Scan{scan}, Meta{}))
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestNewTemplateReporter_Errors(t *testing.T) {
+	// Missing path
+	_, err := NewTemplateReporter("")
+	assert.Error(t, err)
+
+	// Missing file
+	_, err = NewTemplateReporter("does-not-exist.tmpl")
+	assert.Error(t, err)
+
+	// Invalid syntax
+	path := filepath.Join(t.TempDir(), "bad.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte("{{range .Lockfiles}"), 0644))
+	_, err = NewTemplateReporter(path)
+	assert.Error(t, err)
+}
+
+func TestNewView_GraphStats(t *testing.T) {
+	scan := loadTestScan(t, "../../testdata/npm/affected-transitive")
+
+	view := NewView([]Scan{scan}, Meta{ToolVersion: "1.0.0"})
+
+	assert.Equal(t, "1.0.0", view.Tool.Version)
+	assert.Equal(t, 4, view.TotalPackages)
+	assert.Equal(t, 1, view.IssuesFound)
+
+	stats := view.Lockfiles[0].Stats
+	assert.Equal(t, 4, stats.Packages)
+	assert.Equal(t, 3, stats.Edges)
+	assert.Equal(t, 1, stats.Direct)
+	assert.Equal(t, 3, stats.Transitive)
+	assert.Equal(t, 2, stats.MaxDepth)
+}
+
+func TestCountSeverity(t *testing.T) {
+	findings := []FindingView{
+		{Severity: "critical"},
+		{Severity: "high"},
+		{Severity: "critical"},
+	}
+
+	assert.Equal(t, 2, countSeverity(findings, "critical"))
+	assert.Equal(t, 2, countSeverity(findings, "CRITICAL"))
+	assert.Equal(t, 0, countSeverity(findings, "low"))
+}
@@ -0,0 +1,73 @@
+package report
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/graph"
+	"github.com/fullstack-spiderman/hulud-scan/internal/parser"
+	"github.com/fullstack-spiderman/hulud-scan/internal/scanner"
+)
+
+// Scan bundles everything a report needs to describe one scanned lockfile
+type Scan struct {
+	Result       *scanner.ScanResult  // Findings from the blocklist scan
+	Lockfile     *parser.Lockfile     // The parsed lockfile
+	LockfileInfo *parser.LockfileInfo // Where the lockfile was found and its type
+	Graph        *graph.Graph         // Dependency graph built from the lockfile
+}
+
+// Meta describes the scan run itself rather than its findings
+type Meta struct {
+	ToolVersion string    // hulud-scan version
+	StartTime   time.Time // When the scan started
+	EndTime     time.Time // When the scan finished
+}
+
+// identifierType classifies an advisory identifier by its prefix (cve, ghsa, mal, ...)
+func identifierType(id string) string {
+	prefix, _, found := strings.Cut(id, "-")
+	if !found {
+		return "hulud-scan"
+	}
+	return strings.ToLower(prefix)
+}
+
+// identifierURL links an advisory identifier to a public database
+func identifierURL(id string) string {
+	switch identifierType(id) {
+	case "cve":
+		return "https://nvd.nist.gov/vuln/detail/" + id
+	case "ghsa":
+		return "https://github.com/advisories/" + id
+	case "hulud-scan":
+		return ""
+	default:
+		return "https://osv.dev/vulnerability/" + id
+	}
+}
+
+// sortedFindings returns the findings ordered by package name and version
+// ScanGraph walks a map, so its order is not stable between runs
+func sortedFindings(findings []scanner.Finding) []scanner.Finding {
+	sorted := make([]scanner.Finding, len(findings))
+	copy(sorted, findings)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].PackageName != sorted[j].PackageName {
+			return sorted[i].PackageName < sorted[j].PackageName
+		}
+		return sorted[i].Version < sorted[j].Version
+	})
+	return sorted
+}
+
+// findingTitle is a one-line heading for a finding (e.g. "Compromised package lodash@4.17.20")
+func findingTitle(finding scanner.Finding) string {
+	pkgID := finding.PackageName + "@" + finding.Version
+	rule := finding.Rule()
+	if rule.ID == scanner.RuleCompromisedPackage {
+		return "Compromised package " + pkgID
+	}
+	return rule.Title + ": " + pkgID
+}
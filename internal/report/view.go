@@ -0,0 +1,161 @@
+package report
+
+import (
+	"time"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/graph"
+)
+
+// View is the data model handed to user templates (--format template)
+// It is a stable, documented contract: fields may be added but are never
+// renamed or removed, so templates keep working across releases.
+type View struct {
+	Tool          ToolView       // The tool that produced the report
+	StartTime     time.Time      // When the scan started
+	EndTime       time.Time      // When the scan finished
+	TotalPackages int            // Packages scanned across all lockfiles
+	IssuesFound   int            // Findings across all lockfiles
+	Lockfiles     []LockfileView // One entry per scanned lockfile
+}
+
+// ToolView identifies hulud-scan in a template
+type ToolView struct {
+	Name    string // Always "hulud-scan"
+	Version string // Release version (or "dev")
+}
+
+// LockfileView describes one scanned lockfile
+type LockfileView struct {
+	Path           string        // Lockfile path as given on the command line
+	Filename       string        // Lockfile base name (e.g. "package-lock.json")
+	Type           string        // Package manager: npm, yarn, pnpm or bun
+	Project        string        // Project name
+	ProjectVersion string        // Project version
+	TotalPackages  int           // Packages in the lockfile
+	IssuesFound    int           // Number of findings
+	Findings       []FindingView // Findings ordered by package name and version
+	Stats          GraphStatsView
+}
+
+// FindingView describes one flagged package
+type FindingView struct {
+	Rule           string     // Check that produced the finding (e.g. "compromised-package")
+	Package        string     // Package name
+	Version        string     // Flagged version
+	Severity       string     // critical, high, medium, low or info
+	Reason         string     // Why the package was flagged
+	CVE            string     // First CVE identifier, empty when unknown
+	Identifiers    []string   // All advisory identifiers (CVE, GHSA, MAL, ...)
+	CVSSScore      float64    // CVSS base score, 0 when unknown
+	FixedIn        []string   // Versions that fix the issue
+	References     []string   // Advisory and write-up links
+	Tags           []string   // Campaign or category tags (e.g. "shai-hulud-2")
+	Direct         bool       // Is this a direct dependency of the project?
+	Path           []string   // Dependency chain from the project to the package
+	Paths          [][]string // Every chain to the package, shortest first (capped at 100)
+	PathsTruncated bool       // More chains exist than Paths lists
+	EntryPoints    []string   // Direct dependencies that lead to the package
+	Sources        []string   // Blocklist sources (URL or path) that flagged the package
+}
+
+// GraphStatsView summarizes the dependency graph of a lockfile
+type GraphStatsView struct {
+	Packages    int // Nodes in the graph (excluding the project itself)
+	Edges       int // Dependency edges between packages
+	Direct      int // Direct dependencies
+	Transitive  int // Reachable packages that are not direct
+	Unreachable int // Packages not reachable from the project
+	MaxDepth    int // Longest shortest-path distance from the project
+}
+
+// NewView builds the template view model from scan results
+func NewView(scans []Scan, meta Meta) View {
+	view := View{
+		Tool:      ToolView{Name: "hulud-scan", Version: meta.ToolVersion},
+		StartTime: meta.StartTime,
+		EndTime:   meta.EndTime,
+		Lockfiles: make([]LockfileView, 0, len(scans)),
+	}
+
+	for _, scan := range scans {
+		lockfile := LockfileView{
+			Path:           scan.LockfileInfo.Path,
+			Filename:       scan.LockfileInfo.Filename,
+			Type:           string(scan.LockfileInfo.Type),
+			Project:        scan.Lockfile.Name,
+			ProjectVersion: scan.Lockfile.Version,
+			TotalPackages:  scan.Result.TotalPackages,
+			IssuesFound:    scan.Result.IssuesFound,
+			Findings:       make([]FindingView, 0, len(scan.Result.Findings)),
+			Stats:          graphStats(scan.Graph),
+		}
+
+		for _, finding := range sortedFindings(scan.Result.Findings) {
+			lockfile.Findings = append(lockfile.Findings, FindingView{
+				Rule:           finding.Rule().ID,
+				Package:        finding.PackageName,
+				Version:        finding.Version,
+				Severity:       string(finding.Severity),
+				Reason:         finding.Reason,
+				CVE:            finding.CVE(),
+				Identifiers:    finding.Identifiers,
+				CVSSScore:      finding.CVSSScore,
+				FixedIn:        finding.FixedIn,
+				References:     finding.References,
+				Tags:           finding.Tags,
+				Direct:         finding.IsDirect,
+				Path:           finding.Path,
+				Paths:          pathStrings(finding.Paths),
+				PathsTruncated: finding.PathsTruncated,
+				EntryPoints:    finding.EntryPoints,
+				Sources:        finding.Sources,
+			})
+		}
+
+		view.TotalPackages += lockfile.TotalPackages
+		view.IssuesFound += lockfile.IssuesFound
+		view.Lockfiles = append(view.Lockfiles, lockfile)
+	}
+
+	return view
+}
+
+// graphStats counts nodes, edges and depths of a dependency graph
+func graphStats(g *graph.Graph) GraphStatsView {
+	var stats GraphStatsView
+	if g == nil {
+		return stats
+	}
+
+	stats.Packages = len(g.Nodes)
+	for _, node := range g.Nodes {
+		stats.Edges += len(node.Dependencies)
+
+		switch {
+		case node.IsDirect:
+			stats.Direct++
+		case node.Depth == 999:
+			stats.Unreachable++
+		default:
+			stats.Transitive++
+		}
+
+		if node.Depth != 999 && node.Depth > stats.MaxDepth {
+			stats.MaxDepth = node.Depth
+		}
+	}
+
+	return stats
+}
+
+// pathStrings converts dependency paths into plain string lists
+func pathStrings(paths []graph.DependencyPath) [][]string {
+	if len(paths) == 0 {
+		return nil
+	}
+	out := make([][]string, 0, len(paths))
+	for _, path := range paths {
+		out = append(out, path)
+	}
+	return out
+}
@@ -2,49 +2,88 @@ package scanner
 
 import (
 	"crypto/sha256"
-	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 )
 
-const cacheTTL = 1 * time.Hour // Cache for 1 hour
+const cacheTTL = 1 * time.Hour // Default cache lifetime
 
-// loadFromCache loads blocklist from cache if not expired
-func loadFromCache(url string, cacheDir string) (*Blocklist, error) {
+// cacheMeta is the sidecar stored next to each cached blocklist
+// ETag and LastModified let a refresh ask the server whether anything changed.
+type cacheMeta struct {
+	URL          string    `json:"url"`                     // Source URL (the file name is only a hash)
+	ETag         string    `json:"etag,omitempty"`          // ETag response header
+	LastModified string    `json:"last_modified,omitempty"` // Last-Modified response header
+	FetchedAt    time.Time `json:"fetched_at"`              // When the server last confirmed this copy
+}
+
+// readCache returns the cached bytes for a URL, plus its signature if one was cached
+// Unless ignoreExpiry is set (the fallback when a download fails), copies
+// older than ttl count as a miss.
+func readCache(url string, cacheDir string, ttl time.Duration, ignoreExpiry bool) (data, sig []byte, err error) {
 	cachePath := getCachePath(url, cacheDir)
 
 	// Check if cache file exists
 	info, err := os.Stat(cachePath)
-	if os.IsNotExist(err) {
-		return nil, fmt.Errorf("cache miss")
+	if err != nil {
+		return nil, nil, fmt.Errorf("cache miss")
 	}
 
 	// Check if cache is expired
-	if time.Since(info.ModTime()) > cacheTTL {
-		return nil, fmt.Errorf("cache expired")
+	if !ignoreExpiry && time.Since(info.ModTime()) > ttl {
+		return nil, nil, fmt.Errorf("cache expired")
+	}
+
+	data, err = os.ReadFile(cachePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read cache file: %w", err)
 	}
 
-	// Load from cache
-	return LoadBlocklist(cachePath)
+	sig, err = os.ReadFile(cachePath + ".sig")
+	if err != nil {
+		sig = nil // Unsigned source
+	}
+	return data, sig, nil
 }
 
-// loadFromCacheIgnoreExpiry loads cache even if expired (for fallback)
-func loadFromCacheIgnoreExpiry(url string, cacheDir string) (*Blocklist, error) {
-	cachePath := getCachePath(url, cacheDir)
+// readCacheMeta reads the sidecar metadata of a cached URL
+func readCacheMeta(url string, cacheDir string) (cacheMeta, bool) {
+	return readCacheMetaFile(getCachePath(url, cacheDir))
+}
 
-	// Check if cache file exists
-	if _, err := os.Stat(cachePath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("cache miss")
+// readCacheMetaFile reads the sidecar metadata of a cache file
+func readCacheMetaFile(cachePath string) (cacheMeta, bool) {
+	data, err := os.ReadFile(cachePath + ".meta.json")
+	if err != nil {
+		return cacheMeta{}, false
 	}
 
-	// Load from cache (ignore expiry)
-	return LoadBlocklist(cachePath)
+	var meta cacheMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return cacheMeta{}, false
+	}
+	return meta, true
 }
 
-// saveToCache saves blocklist to cache
-func saveToCache(url string, cacheDir string, blocklist *Blocklist) error {
+// CachedAt returns when a URL was last cached, if a cached copy exists
+func CachedAt(url string, cacheDir string) (time.Time, bool) {
+	if cacheDir == "" {
+		return time.Time{}, false
+	}
+	info, err := os.Stat(getCachePath(url, cacheDir))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return info.ModTime(), true
+}
+
+// saveToCache saves the downloaded bytes, detached signature and metadata to cache
+// A nil sig removes any signature cached for an earlier copy. Every file is
+// written atomically, so concurrent scans never read a half-written copy.
+func saveToCache(url string, cacheDir string, data, sig []byte, meta cacheMeta) error {
 	cachePath := getCachePath(url, cacheDir)
 
 	// Ensure cache directory exists
@@ -52,40 +91,70 @@ func saveToCache(url string, cacheDir string, blocklist *Blocklist) error {
 		return fmt.Errorf("failed to create cache dir: %w", err)
 	}
 
-	// Write CSV
-	file, err := os.Create(cachePath)
-	if err != nil {
-		return fmt.Errorf("failed to create cache file: %w", err)
-	}
-	defer func() {
-		if closeErr := file.Close(); closeErr != nil {
-			fmt.Printf("Warning: failed to close cache file: %v\n", closeErr)
+	if sig == nil {
+		if err := os.Remove(cachePath + ".sig"); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove stale signature: %w", err)
 		}
-	}()
+	} else if err := writeFileAtomic(cachePath+".sig", sig); err != nil {
+		return fmt.Errorf("failed to write cached signature: %w", err)
+	}
 
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+	if err := writeFileAtomic(cachePath, data); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
 
-	// Write header
-	if err := writer.Write([]string{"package_name", "version", "severity", "reason", "cve"}); err != nil {
+	return writeCacheMeta(cachePath, meta)
+}
+
+// touchCache marks a cached copy as fresh again after the server reported no change
+func touchCache(url string, cacheDir string, meta cacheMeta) error {
+	cachePath := getCachePath(url, cacheDir)
+	now := time.Now()
+	if err := os.Chtimes(cachePath, now, now); err != nil {
+		return fmt.Errorf("failed to touch cache file: %w", err)
+	}
+	return writeCacheMeta(cachePath, meta)
+}
+
+// writeCacheMeta writes the sidecar metadata for a cache file
+func writeCacheMeta(cachePath string, meta cacheMeta) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
 		return err
 	}
+	if err := writeFileAtomic(cachePath+".meta.json", data); err != nil {
+		return fmt.Errorf("failed to write cache metadata: %w", err)
+	}
+	return nil
+}
 
-	// Write entries
-	for _, entry := range blocklist.Entries {
-		record := []string{
-			entry.PackageName,
-			entry.Version,
-			string(entry.Severity),
-			entry.Reason,
-			entry.CVE,
-		}
-		if err := writer.Write(record); err != nil {
-			return err
-		}
+// writeFileAtomic writes data to a temporary file in the same directory and
+// renames it into place, so readers see either the old or the new content
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
 	}
+	tmpPath := tmp.Name()
 
-	return writer.Error()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
 }
 
 // getCachePath generates cache file path from URL
@@ -0,0 +1,131 @@
+package scanner
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxCacheFileSize caps each file ImportCache extracts, so a hostile archive
+// cannot exhaust memory; cached advisory dumps stay well below it
+var maxCacheFileSize int64 = 1 << 30
+
+// isCacheFile reports whether a file name belongs to the blocklist cache
+// (cached copies, their signatures and metadata sidecars)
+func isCacheFile(name string) bool {
+	return strings.HasPrefix(name, "blocklist-") && !strings.Contains(name, ".tmp-")
+}
+
+// ExportCache writes every cache file into a gzipped tarball
+// Modification times are kept, so freshness survives the trip to another machine.
+func ExportCache(cacheDir string, w io.Writer) (int, error) {
+	files, err := os.ReadDir(cacheDir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read cache dir: %w", err)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	count := 0
+	for _, file := range files {
+		if !file.Type().IsRegular() || !isCacheFile(file.Name()) {
+			continue
+		}
+		if err := addToArchive(tw, filepath.Join(cacheDir, file.Name())); err != nil {
+			return count, fmt.Errorf("failed to export %s: %w", file.Name(), err)
+		}
+		count++
+	}
+
+	if err := tw.Close(); err != nil {
+		return count, err
+	}
+	return count, gz.Close()
+}
+
+// addToArchive appends one file to a tar archive under its base name
+func addToArchive(tw *tar.Writer, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = filepath.Base(path)
+
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	_, err = io.Copy(tw, file)
+	return err
+}
+
+// ImportCache extracts a tarball written by ExportCache into cacheDir
+// Only plain cache files are accepted; anything else (directories, links,
+// paths that would escape the cache directory) is refused.
+func ImportCache(cacheDir string, r io.Reader) (int, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open cache archive: %w", err)
+	}
+	defer func() {
+		_ = gz.Close()
+	}()
+
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create cache dir: %w", err)
+	}
+
+	tr := tar.NewReader(gz)
+	count := 0
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return count, fmt.Errorf("failed to read cache archive: %w", err)
+		}
+
+		if header.Typeflag != tar.TypeReg || header.Name != filepath.Base(header.Name) || !isCacheFile(header.Name) {
+			return count, fmt.Errorf("cache archive contains unexpected entry %q", header.Name)
+		}
+
+		data, err := io.ReadAll(io.LimitReader(tr, maxCacheFileSize+1))
+		if err != nil {
+			return count, fmt.Errorf("failed to read %s from cache archive: %w", header.Name, err)
+		}
+		if int64(len(data)) > maxCacheFileSize {
+			return count, fmt.Errorf("%s in cache archive is larger than %d bytes", header.Name, maxCacheFileSize)
+		}
+
+		path := filepath.Join(cacheDir, header.Name)
+		if err := writeFileAtomic(path, data); err != nil {
+			return count, fmt.Errorf("failed to write %s: %w", header.Name, err)
+		}
+		if err := os.Chtimes(path, header.ModTime, header.ModTime); err != nil {
+			return count, fmt.Errorf("failed to restore time of %s: %w", header.Name, err)
+		}
+		count++
+	}
+
+	return count, nil
+}
@@ -0,0 +1,101 @@
+package scanner
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImportCache(t *testing.T) {
+	// Arrange - a cached copy from yesterday plus an unrelated file
+	source := t.TempDir()
+	url := "https://example.com/list.csv"
+	data := []byte("Package,Version\nevil,= 1.0.0\n")
+	require.NoError(t, saveToCache(url, source, data, nil, cacheMeta{URL: url, ETag: `"v1"`}))
+	yesterday := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	require.NoError(t, os.Chtimes(getCachePath(url, source), yesterday, yesterday))
+	require.NoError(t, os.WriteFile(filepath.Join(source, "notes.txt"), []byte("ignore me"), 0644))
+
+	// Act
+	var archive bytes.Buffer
+	exported, err := ExportCache(source, &archive)
+	require.NoError(t, err)
+
+	target := t.TempDir()
+	imported, err := ImportCache(target, &archive)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, 2, exported, "cache file and metadata sidecar")
+	assert.Equal(t, exported, imported)
+
+	cached, _, err := readCache(url, target, 0, true)
+	require.NoError(t, err)
+	assert.Equal(t, data, cached)
+
+	meta, ok := readCacheMeta(url, target)
+	require.True(t, ok)
+	assert.Equal(t, `"v1"`, meta.ETag)
+
+	cachedAt, ok := CachedAt(url, target)
+	require.True(t, ok)
+	assert.True(t, yesterday.Equal(cachedAt), "age must survive the import")
+	assert.NoFileExists(t, filepath.Join(target, "notes.txt"))
+}
+
+func TestImportCache_RejectsUnexpectedEntries(t *testing.T) {
+	tests := []string{"../blocklist-evil.csv", "sub/blocklist-1.csv", "authorized_keys"}
+
+	for _, name := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			tw := tar.NewWriter(gz)
+			require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: 1}))
+			_, err := tw.Write([]byte("x"))
+			require.NoError(t, err)
+			require.NoError(t, tw.Close())
+			require.NoError(t, gz.Close())
+
+			dir := t.TempDir()
+			_, err = ImportCache(dir, &buf)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "unexpected entry")
+			files, _ := os.ReadDir(dir)
+			assert.Empty(t, files)
+		})
+	}
+}
+
+func TestImportCache_RejectsOversizedEntries(t *testing.T) {
+	// Arrange - an entry just over a lowered cap
+	defer func(limit int64) { maxCacheFileSize = limit }(maxCacheFileSize)
+	maxCacheFileSize = 4
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "blocklist-big.csv", Typeflag: tar.TypeReg, Mode: 0644, Size: 5}))
+	_, err := tw.Write([]byte("12345"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	// Act
+	dir := t.TempDir()
+	_, err = ImportCache(dir, &buf)
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "larger than 4 bytes")
+	files, _ := os.ReadDir(dir)
+	assert.Empty(t, files)
+}
@@ -0,0 +1,137 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CacheEntry describes one cached blocklist
+type CacheEntry struct {
+	Path     string    // Cache file path
+	URL      string    // Source URL from the metadata sidecar (empty if unknown)
+	Size     int64     // Size of the cached copy in bytes
+	CachedAt time.Time // When the copy was last confirmed fresh
+	ETag     string    // ETag the server sent with it
+	Signed   bool      // Whether a detached signature is cached alongside
+	Entries  int       // Number of entries, if the copy parses
+	Err      error     // Why the copy does not parse, if it does not
+}
+
+// ListCache re-parses every cached blocklist and describes it, sorted by URL
+// Copies that no longer parse are reported with Err set instead of failing the listing.
+func ListCache(cacheDir string) ([]CacheEntry, error) {
+	paths, err := cachedCopies(cacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]CacheEntry, 0, len(paths))
+	for _, path := range paths {
+		entries = append(entries, describeCacheFile(path))
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].URL < entries[j].URL
+	})
+	return entries, nil
+}
+
+// ClearCache removes cached blocklists with their signatures and metadata
+// An empty source clears the whole cache; otherwise only that URL's copy is removed.
+func ClearCache(cacheDir string, source string) (int, error) {
+	var paths []string
+	if source == "" {
+		files, err := os.ReadDir(cacheDir)
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read cache dir: %w", err)
+		}
+		for _, file := range files {
+			if file.Type().IsRegular() && strings.HasPrefix(file.Name(), "blocklist-") {
+				paths = append(paths, filepath.Join(cacheDir, file.Name()))
+			}
+		}
+	} else {
+		cachePath := getCachePath(source, cacheDir)
+		paths = []string{cachePath, cachePath + ".sig", cachePath + ".meta.json"}
+	}
+
+	removed := 0
+	for _, path := range paths {
+		err := os.Remove(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", filepath.Base(path), err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// cachedCopies returns the paths of the cached blocklists themselves
+// (not their signatures, metadata or in-progress temp files)
+func cachedCopies(cacheDir string) ([]string, error) {
+	files, err := os.ReadDir(cacheDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache dir: %w", err)
+	}
+
+	var paths []string
+	for _, file := range files {
+		name := file.Name()
+		if !file.Type().IsRegular() || !isCacheFile(name) ||
+			strings.HasSuffix(name, ".sig") || strings.HasSuffix(name, ".meta.json") {
+			continue
+		}
+		paths = append(paths, filepath.Join(cacheDir, name))
+	}
+	return paths, nil
+}
+
+// describeCacheFile reads one cached copy, its sidecars, and parses it
+func describeCacheFile(path string) CacheEntry {
+	entry := CacheEntry{Path: path}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		entry.Err = err
+		return entry
+	}
+	entry.Size = info.Size()
+	entry.CachedAt = info.ModTime()
+
+	if _, err := os.Stat(path + ".sig"); err == nil {
+		entry.Signed = true
+	}
+
+	name := path
+	if meta, ok := readCacheMetaFile(path); ok {
+		entry.URL = meta.URL
+		entry.ETag = meta.ETag
+		name = meta.URL
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		entry.Err = err
+		return entry
+	}
+	blocklist, err := parseBlocklistData(name, data)
+	if err != nil {
+		entry.Err = err
+		return entry
+	}
+	entry.Entries = len(blocklist.Entries)
+	return entry
+}
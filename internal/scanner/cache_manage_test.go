@@ -0,0 +1,69 @@
+package scanner
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCache(t *testing.T) {
+	// Arrange - one good copy with a signature, one corrupted copy
+	cacheDir := t.TempDir()
+	good := "https://example.com/good.csv"
+	bad := "https://example.com/bad.yaml"
+	data := []byte("Package,Version\nevil,= 1.0.0\nworse,= 2.0.0\n")
+	require.NoError(t, saveToCache(good, cacheDir, data, []byte("sig"), cacheMeta{URL: good, ETag: `"v1"`}))
+	require.NoError(t, saveToCache(bad, cacheDir, []byte("entries: [oops"), nil, cacheMeta{URL: bad}))
+
+	// Act
+	entries, err := ListCache(cacheDir)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, bad, entries[0].URL)
+	assert.Error(t, entries[0].Err)
+
+	assert.Equal(t, good, entries[1].URL)
+	assert.NoError(t, entries[1].Err)
+	assert.Equal(t, 2, entries[1].Entries)
+	assert.Equal(t, int64(len(data)), entries[1].Size)
+	assert.Equal(t, `"v1"`, entries[1].ETag)
+	assert.True(t, entries[1].Signed)
+}
+
+func TestListCache_MissingDir(t *testing.T) {
+	entries, err := ListCache(t.TempDir() + "/missing")
+
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestClearCache(t *testing.T) {
+	// Arrange
+	cacheDir := t.TempDir()
+	first := "https://example.com/first.csv"
+	second := "https://example.com/second.csv"
+	data := []byte("Package,Version\nevil,= 1.0.0\n")
+	require.NoError(t, saveToCache(first, cacheDir, data, []byte("sig"), cacheMeta{URL: first}))
+	require.NoError(t, saveToCache(second, cacheDir, data, nil, cacheMeta{URL: second}))
+
+	// Act - one source, then everything
+	removed, err := ClearCache(cacheDir, first)
+	require.NoError(t, err)
+	assert.Equal(t, 3, removed, "copy, signature and metadata")
+	_, ok := CachedAt(second, cacheDir)
+	assert.True(t, ok, "other sources are kept")
+
+	removed, err = ClearCache(cacheDir, "")
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, 2, removed)
+	files, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
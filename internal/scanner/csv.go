@@ -0,0 +1,241 @@
+package scanner
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// CSV columns the scanner understands
+const (
+	columnPackage     = "package"
+	columnVersion     = "version"
+	columnSeverity    = "severity"
+	columnReason      = "reason"
+	columnIdentifiers = "identifiers"
+	columnCVSSScore   = "cvss_score"
+	columnCVSSVector  = "cvss_vector"
+	columnFixedIn     = "fixed_in"
+	columnReferences  = "references"
+	columnPublished   = "published"
+	columnHashes      = "hashes"
+	columnTags        = "tags"
+)
+
+// csvHeaderAliases maps normalized header names (lower case, "_" for spaces and
+// dashes) to columns, so IOC lists with their own naming still load
+var csvHeaderAliases = map[string]string{
+	"package": columnPackage, "package_name": columnPackage, "name": columnPackage,
+	"version": columnVersion, "versions": columnVersion, "affected_versions": columnVersion,
+	"severity": columnSeverity,
+	"reason":   columnReason, "description": columnReason, "summary": columnReason,
+	"cve": columnIdentifiers, "cves": columnIdentifiers, "identifiers": columnIdentifiers,
+	"ids": columnIdentifiers, "id": columnIdentifiers, "ghsa": columnIdentifiers, "advisory": columnIdentifiers,
+	"cvss": columnCVSSScore, "cvss_score": columnCVSSScore,
+	"cvss_vector": columnCVSSVector,
+	"fixed_in":    columnFixedIn, "fixed": columnFixedIn, "patched_versions": columnFixedIn,
+	"references": columnReferences, "reference": columnReferences, "url": columnReferences,
+	"link": columnReferences, "links": columnReferences,
+	"published": columnPublished, "published_at": columnPublished, "date": columnPublished,
+	"first_seen": columnPublished,
+	"hashes":     columnHashes, "hash": columnHashes,
+	"tags": columnTags, "tag": columnTags, "campaign": columnTags,
+}
+
+// csvHashColumns are hash columns named after their algorithm
+var csvHashColumns = []string{"sha256", "sha512", "sha1", "md5"}
+
+// legacyCSVColumns is the positional layout of the original full format,
+// used when a header names no package column
+var legacyCSVColumns = []string{columnPackage, columnVersion, columnSeverity, columnReason, columnIdentifiers}
+
+// csvLayout maps the columns of one CSV file to entry fields
+type csvLayout struct {
+	columns map[string]int // column -> index
+	hashes  map[string]int // hash algorithm -> index of its column
+	width   int            // Columns in the header
+}
+
+// newCSVLayout reads a header row
+// Headers are matched by name (see csvHeaderAliases), so extra or reordered
+// columns are fine. A header without a recognizable package column is read
+// positionally as package_name,version,severity,reason,cve.
+func newCSVLayout(header []string) (*csvLayout, error) {
+	layout := &csvLayout{columns: make(map[string]int), hashes: make(map[string]int), width: len(header)}
+
+	for i, name := range header {
+		name = normalizeHeader(name)
+		if column, ok := csvHeaderAliases[name]; ok {
+			if _, seen := layout.columns[column]; !seen {
+				layout.columns[column] = i
+			}
+			continue
+		}
+		for _, algorithm := range csvHashColumns {
+			if name == algorithm {
+				layout.hashes[algorithm] = i
+			}
+		}
+	}
+
+	if _, ok := layout.columns[columnPackage]; !ok {
+		if len(header) < 4 {
+			return nil, fmt.Errorf("CSV header %q names no package and version columns", strings.Join(header, ","))
+		}
+		layout.columns = make(map[string]int)
+		for i, column := range legacyCSVColumns {
+			if i < len(header) {
+				layout.columns[column] = i
+			}
+		}
+	}
+
+	if _, ok := layout.columns[columnVersion]; !ok {
+		return nil, fmt.Errorf("CSV header %q has no version column", strings.Join(header, ","))
+	}
+	return layout, nil
+}
+
+// normalizeHeader lower-cases a header cell and turns spaces and dashes into "_"
+func normalizeHeader(name string) string {
+	name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+}
+
+// cell returns the trimmed value of a column, or "" if the row is too short
+func (l *csvLayout) cell(record []string, column string) string {
+	i, ok := l.columns[column]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// entries converts one row into blocklist entries, one per listed version
+// Severity and Reason stay empty when the row has none (see applySourceDefaults).
+// A cell that cannot be read is left out of the entries and described in problems,
+// so one bad cell in an upstream list does not cost the whole row.
+func (l *csvLayout) entries(record []string) (entries []BlocklistEntry, problems []string) {
+	base := BlocklistEntry{
+		PackageName: l.cell(record, columnPackage),
+		Severity:    Severity(strings.ToLower(l.cell(record, columnSeverity))),
+		Reason:      l.cell(record, columnReason),
+		Identifiers: splitList(l.cell(record, columnIdentifiers)),
+		CVSSVector:  l.cell(record, columnCVSSVector),
+		FixedIn:     splitList(l.cell(record, columnFixedIn)),
+		References:  splitList(l.cell(record, columnReferences)),
+		Hashes:      splitList(l.cell(record, columnHashes)),
+		Tags:        splitList(l.cell(record, columnTags)),
+	}
+
+	if base.Severity != "" && severityRank(base.Severity) == 0 {
+		problems = append(problems, fmt.Sprintf("unknown severity %q (want critical, high, medium, low or info)", base.Severity))
+		base.Severity = ""
+	}
+
+	if score := l.cell(record, columnCVSSScore); score != "" {
+		if value, err := strconv.ParseFloat(score, 64); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid CVSS score %q", score))
+		} else {
+			base.CVSSScore = value
+		}
+	}
+
+	if published := l.cell(record, columnPublished); published != "" {
+		if value, err := parsePublished(published); err != nil {
+			problems = append(problems, err.Error())
+		} else {
+			base.Published = value
+		}
+	}
+
+	for _, algorithm := range csvHashColumns {
+		if i, ok := l.hashes[algorithm]; ok && i < len(record) {
+			for _, hash := range splitList(record[i]) {
+				base.Hashes = append(base.Hashes, algorithm+":"+hash)
+			}
+		}
+	}
+
+	versions := splitVersions(l.cell(record, columnVersion))
+	if len(versions) == 0 {
+		versions = []string{""} // Reported by validation; never matches
+	}
+
+	entries = make([]BlocklistEntry, 0, len(versions))
+	for _, version := range versions {
+		entry := base
+		entry.Version = version
+		entries = append(entries, entry)
+	}
+	return entries, problems
+}
+
+// parseBlocklistCSV parses CSV into Blocklist
+// The header decides which columns are read (see newCSVLayout); the original
+// full format (package_name,version,severity,reason,cve) and the Wiz format
+// (Package,Version with "= 1.0.0 || = 1.0.1" versions) are both just layouts.
+// Rows without a package or version are skipped. Cells that cannot be read are
+// dropped with a warning; blocklist validate reports them as errors.
+func parseBlocklistCSV(reader *csv.Reader) (*Blocklist, error) {
+	reader.FieldsPerRecord = -1 // Short rows are read as far as they go
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+
+	// Check if file is empty
+	if len(records) < 2 {
+		return nil, fmt.Errorf("blocklist file is empty or missing header")
+	}
+
+	layout, err := newCSVLayout(records[0])
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]BlocklistEntry, 0, len(records)-1)
+	for i, record := range records[1:] {
+		rowEntries, problems := layout.entries(record)
+		for _, problem := range problems {
+			fmt.Fprintf(os.Stderr, "   ⚠️  Blocklist row %d: %s, ignoring the value\n", i+2, problem)
+		}
+		for _, entry := range rowEntries {
+			if entry.PackageName == "" || entry.Version == "" {
+				continue // Skip malformed rows
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	return newBlocklist(entries), nil
+}
+
+// splitVersions splits a version cell such as "= 3.12.5 || = 3.12.6"
+func splitVersions(cell string) []string {
+	versions := make([]string, 0, 1)
+	for _, part := range strings.Split(cell, "||") {
+		part = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(part), "="))
+		if part != "" {
+			versions = append(versions, part)
+		}
+	}
+	return versions
+}
+
+// splitList splits a multi-value cell such as "CVE-1;GHSA-2"
+func splitList(cell string) []string {
+	values := make([]string, 0, 1)
+	for _, value := range strings.Split(cell, ";") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
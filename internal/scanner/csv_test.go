@@ -0,0 +1,87 @@
+package scanner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBlocklistData_ExtraColumns(t *testing.T) {
+	// Arrange - reordered, renamed and extra columns
+	data := []byte(`Name,Affected Versions,Severity,Description,IDs,Fixed,Link,First Seen,SHA256,Campaign,Notes
+@ctrl/tinycolor,= 4.1.1 || = 4.1.2,High,Worm payload,GHSA-1;CVE-2025-1,4.1.3,https://example.com/a;https://example.com/b,2025-09-15,abc123,shai-hulud,ignored
+`)
+
+	// Act
+	blocklist, err := parseBlocklistData("iocs.csv", data)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, blocklist.Entries, 2)
+
+	entry := blocklist.Entries[1]
+	assert.Equal(t, "@ctrl/tinycolor", entry.PackageName)
+	assert.Equal(t, "4.1.2", entry.Version)
+	assert.Equal(t, SeverityHigh, entry.Severity)
+	assert.Equal(t, "Worm payload", entry.Reason)
+	assert.Equal(t, []string{"GHSA-1", "CVE-2025-1"}, entry.Identifiers)
+	assert.Equal(t, []string{"4.1.3"}, entry.FixedIn)
+	assert.Equal(t, []string{"https://example.com/a", "https://example.com/b"}, entry.References)
+	assert.Equal(t, time.Date(2025, 9, 15, 0, 0, 0, 0, time.UTC), entry.Published)
+	assert.Equal(t, []string{"sha256:abc123"}, entry.Hashes)
+	assert.Equal(t, []string{"shai-hulud"}, entry.Tags)
+}
+
+func TestParseBlocklistData_CSVBadCells(t *testing.T) {
+	// Arrange - unreadable optional cells next to a usable package and version
+	data := []byte("package,version,severity,reason,cvss_score,published\n" +
+		"evil,1.0.0,urgent,Worm,high,yesterday\n" +
+		"good,2.0.0,high,Worm,7.5,2025-09-15\n")
+
+	// Act
+	blocklist, err := parseBlocklistData("list.csv", data)
+
+	// Assert - the rows stay, the bad cells are dropped
+	require.NoError(t, err)
+	require.Len(t, blocklist.Entries, 2)
+
+	evil := blocklist.Entries[0]
+	assert.Equal(t, "evil", evil.PackageName)
+	assert.Equal(t, "Worm", evil.Reason)
+	assert.Empty(t, evil.Severity, "unknown severities are left to the source default")
+	assert.Zero(t, evil.CVSSScore)
+	assert.True(t, evil.Published.IsZero())
+
+	assert.Equal(t, SeverityHigh, blocklist.Entries[1].Severity)
+	assert.Equal(t, 7.5, blocklist.Entries[1].CVSSScore)
+}
+
+func TestParseBlocklistData_CSVHeaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		entries     int
+		expectedErr string
+	}{
+		{"wiz", "Package,Version\nevil,= 1.0.0\n", 1, ""},
+		{"legacy positional", "pkg,ver,sev,why\nevil,1.0.0,high,worm\n", 1, ""},
+		{"rows without version are skipped", "package,version\nevil,\ngood,1.0.0\n", 1, ""},
+		{"no package column", "foo,bar\nevil,1.0.0\n", 0, "names no package and version columns"},
+		{"no version column", "package,severity,reason,cve\nevil,high,worm,\n", 0, "has no version column"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocklist, err := parseBlocklistData("list.csv", []byte(tt.data))
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, blocklist.Entries, tt.entries)
+		})
+	}
+}
@@ -1,92 +1,55 @@
 package scanner
 
 import (
-	"encoding/csv"
-	"fmt"
-	"io"
-	"net/http"
 	"strings"
-	"time"
 )
 
 // DownloadBlocklist downloads a blocklist from a URL
 func DownloadBlocklist(url string) (*Blocklist, error) {
-	// Convert GitHub web URL to raw URL if needed
-	url = convertToRawURL(url)
-
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
-
-	fmt.Printf("   Downloading from: %s\n", url)
-
-	// Download the CSV
-	resp, err := client.Get(url)
+	data, err := fetchBlocklist(url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to download blocklist: %w", err)
+		return nil, err
 	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			fmt.Printf("Warning: failed to close response body: %v\n", closeErr)
-		}
-	}()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to download blocklist: HTTP %d", resp.StatusCode)
+	blocklist, err := parseBlocklistData(url, data)
+	if err != nil {
+		return nil, err
 	}
+	Source{Location: url}.applyDefaults(blocklist.Entries)
+	return blocklist, nil
+}
 
-	// Read response body
-	data, err := io.ReadAll(resp.Body)
+// fetchBlocklist downloads the raw blocklist bytes from a URL
+func fetchBlocklist(url string) ([]byte, error) {
+	result, err := defaultFetcher.fetchIfChanged(url, nil, cacheMeta{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, err
 	}
+	return result.Data, nil
+}
 
-	// Parse CSV
-	reader := csv.NewReader(strings.NewReader(string(data)))
-	return parseBlocklistCSV(reader)
+// fetchResult is the outcome of a (possibly conditional) download
+type fetchResult struct {
+	Data         []byte // Response body (nil when NotModified)
+	ETag         string // ETag response header
+	LastModified string // Last-Modified response header
+	NotModified  bool   // Server answered 304: the cached copy is current
+	Signature    []byte // Detached signature fetched along with the data (git, OCI)
 }
 
 // LoadOrDownloadBlocklist loads from file or downloads from URL
+// Every returned entry records path as its source. Use a Loader for
+// signature checks.
 func LoadOrDownloadBlocklist(path string, cacheDir string) (*Blocklist, error) {
-	// Check if it's a URL
-	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
-		// Check cache first
-		if cacheDir != "" {
-			cached, err := loadFromCache(path, cacheDir)
-			if err == nil {
-				fmt.Printf("   Using cached blocklist\n")
-				return cached, nil
-			}
-		}
-
-		// Download
-		blocklist, err := DownloadBlocklist(path)
-		if err != nil {
-			// Try to use expired cache as fallback
-			if cacheDir != "" {
-				cached, cacheErr := loadFromCacheIgnoreExpiry(path, cacheDir)
-				if cacheErr == nil {
-					fmt.Printf("   ⚠️  Download failed, using cached version (may be outdated)\n")
-					return cached, nil
-				}
-			}
-			return nil, err
-		}
-
-		// Save to cache
-		if cacheDir != "" {
-			if err := saveToCache(path, cacheDir, blocklist); err != nil {
-				// Non-fatal - just log
-				fmt.Printf("   Warning: failed to cache blocklist: %v\n", err)
-			}
-		}
-
-		return blocklist, nil
-	}
+	loader := &Loader{CacheDir: cacheDir}
+	return loader.Load(path)
+}
 
-	// Load from local file
-	return LoadBlocklist(path)
+// IsRemote reports whether a blocklist location is a URL rather than a local path
+// Besides http(s), git ("git+https://...#path@ref") and OCI ("oci://...") sources are remote.
+func IsRemote(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") ||
+		isGitLocation(location) || isOCILocation(location)
 }
 
 // convertToRawURL converts GitHub web URLs to raw content URLs
@@ -32,7 +32,7 @@ express,= 4.17.1`
 	assert.Equal(t, "lodash", blocklist.Entries[0].PackageName)
 	assert.Equal(t, "4.17.20", blocklist.Entries[0].Version)
 	assert.Equal(t, SeverityCritical, blocklist.Entries[0].Severity)
-	assert.Contains(t, blocklist.Entries[0].Reason, "Shai-Hulud")
+	assert.Equal(t, defaultReason, blocklist.Entries[0].Reason, "the Wiz list has no reason column")
 }
 
 func TestDownloadBlocklist_FullFormat(t *testing.T) {
@@ -51,7 +51,7 @@ express,4.17.1,high,DoS vulnerability,CVE-2022-24999`
 	require.NoError(t, err)
 	assert.Len(t, blocklist.Entries, 2)
 	assert.Equal(t, "Prototype pollution", blocklist.Entries[0].Reason)
-	assert.Equal(t, "CVE-2020-8203", blocklist.Entries[0].CVE)
+	assert.Equal(t, []string{"CVE-2020-8203"}, blocklist.Entries[0].Identifiers)
 }
 
 func TestDownloadBlocklist_HTTPError(t *testing.T) {
@@ -118,11 +118,12 @@ test-package,= 1.0.0`
 	require.NoError(t, err)
 	assert.Equal(t, "test-package", blocklist1.Entries[0].PackageName)
 
-	// Verify cache file was created
+	// Verify cache file and its metadata sidecar were created
 	files, err := os.ReadDir(tmpDir)
 	require.NoError(t, err)
-	assert.Len(t, files, 1)
+	assert.Len(t, files, 2)
 	assert.Contains(t, files[0].Name(), "blocklist-")
+	assert.Equal(t, files[0].Name()+".meta.json", files[1].Name())
 
 	// Second load - should use cache (within TTL)
 	blocklist2, err := LoadOrDownloadBlocklist(server.URL, tmpDir)
@@ -162,12 +163,97 @@ test,= 1.0.0`
 	assert.Equal(t, 2, downloadCount, "Should have downloaded twice due to cache expiry")
 }
 
-func TestParseWizEntry_MultipleVersions(t *testing.T) {
-	record := []string{"test-package", "= 1.0.0 || = 1.0.1"}
+func TestParseBlocklistData_WizMultipleVersions(t *testing.T) {
+	data := []byte("Package,Version\ntest-package,= 1.0.0 || = 1.0.1\n")
 
-	entry := parseWizEntry(record)
+	blocklist, err := parseBlocklistData("wiz.csv", data)
 
-	assert.Equal(t, "test-package", entry.PackageName)
-	assert.Equal(t, "1.0.0", entry.Version) // Should take first version
-	assert.Equal(t, SeverityCritical, entry.Severity)
+	require.NoError(t, err)
+	require.Len(t, blocklist.Entries, 2, "every listed version is kept")
+	assert.Equal(t, "test-package", blocklist.Entries[0].PackageName)
+	assert.Equal(t, "1.0.0", blocklist.Entries[0].Version)
+	assert.Equal(t, "1.0.1", blocklist.Entries[1].Version)
+	assert.NotNil(t, blocklist.IsBlocked("test-package", "1.0.1"))
+}
+
+func TestLoadOrDownloadBlocklist_ConditionalRefresh(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	fullDownloads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fullDownloads++
+		_, _ = w.Write([]byte("Package,Version\ntest,= 1.0.0"))
+	}))
+	defer server.Close()
+
+	// First load stores the ETag
+	_, err := LoadOrDownloadBlocklist(server.URL, tmpDir)
+	require.NoError(t, err)
+	meta, ok := readCacheMeta(server.URL, tmpDir)
+	require.True(t, ok)
+	assert.Equal(t, `"v1"`, meta.ETag)
+	assert.Equal(t, server.URL, meta.URL)
+
+	// Expire the cache; the refresh is answered with 304
+	cacheFile := getCachePath(server.URL, tmpDir)
+	oldTime := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(cacheFile, oldTime, oldTime))
+
+	blocklist, err := LoadOrDownloadBlocklist(server.URL, tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, "test", blocklist.Entries[0].PackageName)
+	assert.Equal(t, 1, fullDownloads, "unchanged list must not be downloaded again")
+
+	// The cached copy is fresh again
+	info, err := os.Stat(cacheFile)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), info.ModTime(), time.Minute)
+}
+
+func TestLoader_PerSourceTTL(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	downloads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads++
+		_, _ = w.Write([]byte("Package,Version\ntest,= 1.0.0"))
+	}))
+	defer server.Close()
+
+	loader := &Loader{CacheDir: tmpDir, TTL: 24 * time.Hour}
+	_, err := loader.LoadSource(Source{Location: server.URL})
+	require.NoError(t, err)
+
+	// 10 minutes old: fresh for the loader default, stale for a 5-minute source
+	tenMinutesAgo := time.Now().Add(-10 * time.Minute)
+	require.NoError(t, os.Chtimes(getCachePath(server.URL, tmpDir), tenMinutesAgo, tenMinutesAgo))
+
+	_, err = loader.LoadSource(Source{Location: server.URL})
+	require.NoError(t, err)
+	assert.Equal(t, 1, downloads, "default TTL keeps the cached copy")
+
+	_, err = loader.LoadSource(Source{Location: server.URL, TTL: 5 * time.Minute})
+	require.NoError(t, err)
+	assert.Equal(t, 2, downloads, "source TTL forces a refresh")
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "blocklist-0000.csv")
+
+	require.NoError(t, writeFileAtomic(path, []byte("old")))
+	require.NoError(t, writeFileAtomic(path, []byte("new")))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1, "no temporary files are left behind")
 }
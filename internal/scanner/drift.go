@@ -0,0 +1,96 @@
+package scanner
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/graph"
+	"github.com/fullstack-spiderman/hulud-scan/internal/parser"
+	"github.com/fullstack-spiderman/hulud-scan/internal/semver"
+)
+
+// CheckDrift compares the lockfile with the package.json next to it
+// It reports dependencies package.json declares that the lockfile does not
+// install, dependencies in the lockfile's root entry that package.json does
+// not declare, and resolved versions outside the declared range. Package
+// managers keep the two in sync, so drift points at a hand-edited or tampered
+// lockfile. Without a package.json there is nothing to compare.
+func CheckDrift(lockfile *parser.Lockfile, g *graph.Graph) []Finding {
+	findings := make([]Finding, 0)
+	manifest := lockfile.Manifest
+	if manifest == nil {
+		return findings
+	}
+
+	declared := manifest.Installed()
+	for _, name := range sortedNames(declared) {
+		spec := declared[name]
+
+		version, ok := lockfile.ResolveRoot(name, spec)
+		if !ok {
+			if !manifest.IsOptional(name) {
+				findings = append(findings, driftFinding(g, RuleMissingDependency, name, spec,
+					fmt.Sprintf("package.json declares %s@%s but the lockfile does not install it", name, spec)))
+			}
+			continue
+		}
+
+		if recorded, ok := lockfile.RootDependencies[name]; ok && recorded != spec {
+			findings = append(findings, driftFinding(g, RuleRangeMismatch, name, version,
+				fmt.Sprintf("package.json declares %s but the lockfile records %s", spec, recorded)))
+			continue
+		}
+
+		// Specs that are not ranges (tags, git URLs, file: and npm: specs) cannot be checked
+		if satisfied, err := semver.Satisfies(version, spec); err == nil && !satisfied {
+			findings = append(findings, driftFinding(g, RuleRangeMismatch, name, version,
+				fmt.Sprintf("%s@%s does not satisfy %s declared in package.json", name, version, spec)))
+		}
+	}
+
+	for _, name := range sortedNames(lockfile.RootDependencies) {
+		if manifest.Declares(name) {
+			continue
+		}
+		version := lockfile.RootDependencies[name]
+		if pkg, ok := lockfile.Packages["node_modules/"+name]; ok {
+			version = pkg.Version
+		}
+		findings = append(findings, driftFinding(g, RuleUndeclaredDependency, name, version,
+			fmt.Sprintf("the lockfile's root entry lists %s@%s but package.json does not declare it", name, lockfile.RootDependencies[name])))
+	}
+
+	return findings
+}
+
+// driftFinding builds a finding for a project dependency
+// version is the installed version, or the declared range when nothing is installed.
+func driftFinding(g *graph.Graph, ruleID, name, version, reason string) Finding {
+	rule := LookupRule(ruleID)
+	finding := Finding{
+		RuleID:      ruleID,
+		PackageName: name,
+		Version:     version,
+		Path:        graph.DependencyPath{g.Root.Package.Name, name},
+		Severity:    rule.Severity,
+		Reason:      reason,
+		IsDirect:    true,
+	}
+
+	path := "node_modules/" + name
+	if node, ok := g.Nodes[path]; ok {
+		finding.PackagePath = path
+		finding.Line = node.Package.Line
+	}
+	return finding
+}
+
+// sortedNames returns the keys of a dependency map in order
+func sortedNames(deps map[string]string) []string {
+	names := make([]string, 0, len(deps))
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
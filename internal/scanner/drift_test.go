@@ -0,0 +1,156 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/graph"
+	"github.com/fullstack-spiderman/hulud-scan/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// driftLockfile is an npm-style lockfile with a root entry and package.json
+func driftLockfile(root map[string]string, manifest *parser.Manifest, installed map[string]string) *parser.Lockfile {
+	lockfile := &parser.Lockfile{
+		Name:               "app",
+		Version:            "1.0.0",
+		DirectDependencies: root,
+		RootDependencies:   root,
+		Packages:           make(map[string]*parser.Package),
+		Manifest:           manifest,
+	}
+	for name, version := range installed {
+		lockfile.Packages["node_modules/"+name] = &parser.Package{Name: name, Version: version, Line: 10}
+	}
+	return lockfile
+}
+
+// checkDrift builds the graph and runs the drift check
+func checkDrift(t *testing.T, lockfile *parser.Lockfile) []Finding {
+	t.Helper()
+	g, err := graph.BuildGraph(lockfile)
+	require.NoError(t, err)
+	return CheckDrift(lockfile, g)
+}
+
+func TestCheckDrift_InSync(t *testing.T) {
+	lockfile := driftLockfile(
+		map[string]string{"lodash": "^4.17.0", "jest": "^29.0.0"},
+		&parser.Manifest{
+			Dependencies:    map[string]string{"lodash": "^4.17.0"},
+			DevDependencies: map[string]string{"jest": "^29.0.0"},
+		},
+		map[string]string{"lodash": "4.17.21", "jest": "29.7.0"},
+	)
+
+	assert.Empty(t, checkDrift(t, lockfile))
+}
+
+func TestCheckDrift_MissingDependency(t *testing.T) {
+	// Arrange - express is declared but neither recorded nor installed; fsevents is optional
+	lockfile := driftLockfile(
+		map[string]string{"lodash": "^4.17.0"},
+		&parser.Manifest{
+			Dependencies:         map[string]string{"lodash": "^4.17.0", "express": "^4.18.0"},
+			OptionalDependencies: map[string]string{"fsevents": "^2.3.0"},
+		},
+		map[string]string{"lodash": "4.17.21"},
+	)
+
+	// Act
+	findings := checkDrift(t, lockfile)
+
+	// Assert
+	require.Len(t, findings, 1)
+	assert.Equal(t, RuleMissingDependency, findings[0].RuleID)
+	assert.Equal(t, "express", findings[0].PackageName)
+	assert.Equal(t, "^4.18.0", findings[0].Version, "the declared range stands in for the missing version")
+	assert.Equal(t, SeverityHigh, findings[0].Severity)
+	assert.Equal(t, graph.DependencyPath{"app", "express"}, findings[0].Path)
+	assert.Zero(t, findings[0].Line)
+}
+
+func TestCheckDrift_UndeclaredDependency(t *testing.T) {
+	// Arrange - the root entry lists a package package.json never declared
+	lockfile := driftLockfile(
+		map[string]string{"lodash": "^4.17.0", "evil": "^1.0.0"},
+		&parser.Manifest{Dependencies: map[string]string{"lodash": "^4.17.0"}},
+		map[string]string{"lodash": "4.17.21", "evil": "1.0.0"},
+	)
+
+	// Act
+	findings := checkDrift(t, lockfile)
+
+	// Assert
+	require.Len(t, findings, 1)
+	assert.Equal(t, RuleUndeclaredDependency, findings[0].RuleID)
+	assert.Equal(t, "evil", findings[0].PackageName)
+	assert.Equal(t, "1.0.0", findings[0].Version)
+	assert.Equal(t, "node_modules/evil", findings[0].PackagePath)
+	assert.Equal(t, 10, findings[0].Line)
+}
+
+func TestCheckDrift_RangeMismatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		root      string
+		installed string
+		reason    string
+	}{
+		{"version outside range", "^4.17.0", "3.10.1", "lodash@3.10.1 does not satisfy ^4.17.0 declared in package.json"},
+		{"different recorded range", "^3.0.0", "3.10.1", "package.json declares ^4.17.0 but the lockfile records ^3.0.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lockfile := driftLockfile(
+				map[string]string{"lodash": tt.root},
+				&parser.Manifest{Dependencies: map[string]string{"lodash": "^4.17.0"}},
+				map[string]string{"lodash": tt.installed},
+			)
+
+			findings := checkDrift(t, lockfile)
+
+			require.Len(t, findings, 1)
+			assert.Equal(t, RuleRangeMismatch, findings[0].RuleID)
+			assert.Equal(t, tt.installed, findings[0].Version)
+			assert.Equal(t, tt.reason, findings[0].Reason)
+		})
+	}
+}
+
+func TestCheckDrift_Specifiers(t *testing.T) {
+	// Arrange - a yarn-style lockfile: no root entry, versions looked up by "name@range"
+	lockfile := &parser.Lockfile{
+		Name:       "app",
+		Specifiers: map[string]string{"lodash@^4.17.0": "4.17.21", "react@^18.0.0": "17.0.2", "left-pad@github:user/left-pad": "1.3.0"},
+		Packages: map[string]*parser.Package{
+			"node_modules/lodash":   {Name: "lodash", Version: "3.10.1"}, // Another copy took the path
+			"node_modules/react":    {Name: "react", Version: "17.0.2"},
+			"node_modules/left-pad": {Name: "left-pad", Version: "1.3.0"},
+		},
+		Manifest: &parser.Manifest{Dependencies: map[string]string{
+			"lodash": "^4.17.0", "react": "^18.0.0", "left-pad": "github:user/left-pad",
+		}},
+	}
+
+	// Act
+	findings := checkDrift(t, lockfile)
+
+	// Assert
+	require.Len(t, findings, 1, "lodash resolves through its spec and git specs are not ranges")
+	assert.Equal(t, RuleRangeMismatch, findings[0].RuleID)
+	assert.Equal(t, "react", findings[0].PackageName)
+}
+
+func TestCheckDrift_NoManifest(t *testing.T) {
+	lockfile := driftLockfile(map[string]string{"lodash": "^4.17.0"}, nil, nil)
+
+	assert.Empty(t, checkDrift(t, lockfile))
+}
+
+func TestLookupRule(t *testing.T) {
+	assert.Equal(t, "LockfileRangeMismatch", LookupRule(RuleRangeMismatch).Name)
+	assert.Equal(t, RuleCompromisedPackage, LookupRule("").ID, "findings without a rule come from the blocklist")
+	assert.Equal(t, RuleCompromisedPackage, Finding{}.Rule().ID)
+}
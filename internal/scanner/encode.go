@@ -0,0 +1,230 @@
+package scanner
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FormatOSV writes entries as an array of OSV records (read back as FormatAdvisory)
+const FormatOSV = "osv"
+
+// WriteFormats lists the formats WriteBlocklist can produce
+func WriteFormats() []string {
+	return []string{FormatCSV, FormatNativeJSON, FormatNativeYAML, FormatOSV}
+}
+
+// WriteBlocklist encodes a blocklist in the given format
+// CSV has no way to express version ranges, so range-only entries are left out
+// of CSV output; skipped reports how many entries that affected.
+func WriteBlocklist(w io.Writer, blocklist *Blocklist, format string) (skipped int, err error) {
+	switch format {
+	case FormatCSV:
+		return writeBlocklistCSV(w, blocklist)
+	case FormatNativeJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return 0, encoder.Encode(nativeDocument(blocklist))
+	case FormatNativeYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(nativeDocument(blocklist)); err != nil {
+			return 0, err
+		}
+		return 0, encoder.Close()
+	case FormatOSV:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return 0, encoder.Encode(osvDocument(blocklist))
+	default:
+		return 0, fmt.Errorf("unknown blocklist format %q (available: %s)", format, strings.Join(WriteFormats(), ", "))
+	}
+}
+
+// writeBlocklistCSV writes the full CSV format
+func writeBlocklistCSV(w io.Writer, blocklist *Blocklist) (int, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"package_name", "version", "severity", "reason", "cve"}); err != nil {
+		return 0, err
+	}
+
+	skipped := 0
+	for _, entry := range blocklist.Entries {
+		if entry.Version == "" {
+			skipped++
+			continue
+		}
+		record := []string{entry.PackageName, entry.Version, string(entry.Severity), entry.Reason,
+			strings.Join(entry.Identifiers, ";")}
+		if err := writer.Write(record); err != nil {
+			return skipped, err
+		}
+	}
+
+	writer.Flush()
+	return skipped, writer.Error()
+}
+
+// nativeDocument converts a blocklist into the native schema
+func nativeDocument(blocklist *Blocklist) nativeBlocklist {
+	doc := nativeBlocklist{
+		SchemaVersion: NativeSchemaVersion,
+		Entries:       make([]nativeEntry, 0, len(blocklist.Entries)),
+	}
+
+	for _, entry := range blocklist.Entries {
+		item := nativeEntry{
+			Package:     entry.PackageName,
+			Severity:    entry.Severity,
+			Reason:      entry.Reason,
+			Identifiers: entry.Identifiers,
+			CVSSScore:   entry.CVSSScore,
+			CVSSVector:  entry.CVSSVector,
+			FixedIn:     entry.FixedIn,
+			References:  entry.References,
+			Published:   formatPublished(entry.Published),
+			Hashes:      entry.Hashes,
+			Tags:        entry.Tags,
+		}
+		if entry.Version != "" {
+			item.Versions = []string{entry.Version}
+		}
+		for _, r := range entry.Ranges {
+			item.Ranges = append(item.Ranges, nativeRange(r))
+		}
+		doc.Entries = append(doc.Entries, item)
+	}
+
+	return doc
+}
+
+// formatPublished writes a date when there is no time of day, RFC 3339 otherwise
+func formatPublished(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	if t.Equal(t.Truncate(24 * time.Hour)) {
+		return t.UTC().Format("2006-01-02")
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// osvOutput is an OSV record as written by WriteBlocklist
+type osvOutput struct {
+	SchemaVersion    string            `json:"schema_version"`
+	ID               string            `json:"id"`
+	Published        string            `json:"published,omitempty"`
+	Aliases          []string          `json:"aliases,omitempty"`
+	Summary          string            `json:"summary,omitempty"`
+	Severity         []osvOutputScore  `json:"severity,omitempty"`
+	Affected         []osvOutputTarget `json:"affected"`
+	References       []osvOutputLink   `json:"references,omitempty"`
+	DatabaseSpecific map[string]any    `json:"database_specific,omitempty"`
+}
+
+type osvOutputScore struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+type osvOutputTarget struct {
+	Package  osvOutputPackage `json:"package"`
+	Ranges   []osvOutputRange `json:"ranges,omitempty"`
+	Versions []string         `json:"versions,omitempty"`
+}
+
+type osvOutputPackage struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+}
+
+type osvOutputRange struct {
+	Type   string              `json:"type"`
+	Events []map[string]string `json:"events"`
+}
+
+type osvOutputLink struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// osvDocument converts a blocklist into OSV records, one per entry
+// Entries without an advisory identifier get a generated HULUD-<n> id.
+func osvDocument(blocklist *Blocklist) []osvOutput {
+	records := make([]osvOutput, 0, len(blocklist.Entries))
+
+	for i, entry := range blocklist.Entries {
+		record := osvOutput{
+			SchemaVersion: "1.6.0",
+			ID:            fmt.Sprintf("HULUD-%d", i+1),
+			Summary:       entry.Reason,
+			DatabaseSpecific: map[string]any{
+				"severity": osvSeverityName(entry.Severity),
+			},
+		}
+		if len(entry.Identifiers) > 0 {
+			record.ID = entry.Identifiers[0]
+			record.Aliases = entry.Identifiers[1:]
+		}
+		if !entry.Published.IsZero() {
+			record.Published = entry.Published.UTC().Format(time.RFC3339)
+		}
+		if entry.CVSSVector != "" {
+			record.Severity = []osvOutputScore{{Type: "CVSS_V3", Score: entry.CVSSVector}}
+		}
+		for _, ref := range entry.References {
+			record.References = append(record.References, osvOutputLink{Type: "WEB", URL: ref})
+		}
+		if len(entry.Tags) > 0 {
+			record.DatabaseSpecific["tags"] = entry.Tags
+		}
+
+		target := osvOutputTarget{Package: osvOutputPackage{Ecosystem: "npm", Name: entry.PackageName}}
+		if entry.Version != "" {
+			target.Versions = []string{entry.Version}
+		}
+		for _, r := range entry.Ranges {
+			target.Ranges = append(target.Ranges, osvOutputRange{Type: "SEMVER", Events: osvEvents(r)})
+		}
+		record.Affected = []osvOutputTarget{target}
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// osvEvents turns a range back into OSV events
+func osvEvents(r VersionRange) []map[string]string {
+	introduced := r.Introduced
+	if introduced == "" {
+		introduced = "0"
+	}
+	events := []map[string]string{{"introduced": introduced}}
+	if r.Fixed != "" {
+		events = append(events, map[string]string{"fixed": r.Fixed})
+	}
+	if r.LastAffected != "" {
+		events = append(events, map[string]string{"last_affected": r.LastAffected})
+	}
+	return events
+}
+
+// osvSeverityName maps our severity onto the GitHub/OSV severity names
+func osvSeverityName(severity Severity) string {
+	switch severity {
+	case SeverityCritical:
+		return "CRITICAL"
+	case SeverityHigh:
+		return "HIGH"
+	case SeverityMedium:
+		return "MODERATE"
+	default:
+		return "LOW"
+	}
+}
@@ -0,0 +1,97 @@
+package scanner
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readTestFile reads a fixture or fails the test
+func readTestFile(t *testing.T, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return data
+}
+
+func TestWriteBlocklist_RoundTrip(t *testing.T) {
+	source, err := LoadBlocklist("../../testdata/blocklists/shai-hulud-2.yaml")
+	require.NoError(t, err)
+
+	for _, format := range []string{FormatNativeJSON, FormatNativeYAML, FormatOSV} {
+		t.Run(format, func(t *testing.T) {
+			// Act
+			var buf bytes.Buffer
+			skipped, err := WriteBlocklist(&buf, source, format)
+			require.NoError(t, err)
+			assert.Zero(t, skipped)
+
+			name := "out." + format
+			if format == FormatOSV {
+				name = "out.json"
+			}
+			loaded, err := parseBlocklistData(name, buf.Bytes())
+
+			// Assert - everything that decides a match survives the trip
+			require.NoError(t, err)
+			require.Len(t, loaded.Entries, len(source.Entries))
+			for i, entry := range source.Entries {
+				got := loaded.Entries[i]
+				assert.Equal(t, entry.PackageName, got.PackageName)
+				assert.Equal(t, entry.Version, got.Version)
+				assert.Equal(t, entry.Ranges, got.Ranges)
+				assert.Equal(t, entry.Severity, got.Severity)
+				assert.Equal(t, entry.Identifiers, got.Identifiers)
+				assert.Equal(t, entry.References, got.References)
+				assert.Equal(t, entry.Tags, got.Tags)
+				assert.True(t, entry.Published.Equal(got.Published))
+			}
+		})
+	}
+}
+
+func TestWriteBlocklist_CSVSkipsRanges(t *testing.T) {
+	source, err := LoadBlocklist("../../testdata/blocklists/shai-hulud-2.yaml")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	skipped, err := WriteBlocklist(&buf, source, FormatCSV)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, skipped, "the lodash range has no CSV form")
+	assert.Equal(t, `package_name,version,severity,reason,cve
+@ctrl/tinycolor,4.1.1,critical,Worm payload in bundle.js (Shai-Hulud),MAL-2025-50000
+@ctrl/tinycolor,4.1.2,critical,Worm payload in bundle.js (Shai-Hulud),MAL-2025-50000
+`, buf.String())
+}
+
+func TestWriteBlocklist_UnknownFormat(t *testing.T) {
+	_, err := WriteBlocklist(&bytes.Buffer{}, newBlocklist(nil), "xml")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown blocklist format "xml"`)
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{"list.csv", "package_name,version,severity,reason\n", FormatCSV},
+		{"list.yml", "entries: []\n", FormatNativeYAML},
+		{"cached.csv", "schema_version: 1\nentries: []\n", FormatNativeYAML},
+		{"list.json", `{"schema_version": 1, "entries": []}`, FormatNativeJSON},
+		{"osv.json", `{"schema_version": "1.6.0", "id": "MAL-1", "affected": []}`, FormatAdvisory},
+		{"all.zip", "PK\x03\x04", FormatZip},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DetectFormat(tt.name, []byte(tt.data)))
+		})
+	}
+}
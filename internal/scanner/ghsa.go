@@ -0,0 +1,246 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/semver"
+)
+
+// ghsaAdvisory is the subset of a GitHub REST API advisory we use
+// (GET /advisories and GET /repos/{owner}/{repo}/security-advisories).
+type ghsaAdvisory struct {
+	GHSAID      string     `json:"ghsa_id"`
+	CVEID       string     `json:"cve_id"`
+	HTMLURL     string     `json:"html_url"`
+	Summary     string     `json:"summary"`
+	Description string     `json:"description"`
+	Severity    string     `json:"severity"`
+	PublishedAt time.Time  `json:"published_at"`
+	WithdrawnAt *time.Time `json:"withdrawn_at"`
+	References  []string   `json:"references"`
+	Identifiers []struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	} `json:"identifiers"`
+	Vulnerabilities []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		VulnerableVersionRange string          `json:"vulnerable_version_range"`
+		FirstPatchedVersion    json.RawMessage `json:"first_patched_version"`
+	} `json:"vulnerabilities"`
+	CVSS struct {
+		VectorString string  `json:"vector_string"`
+		Score        float64 `json:"score"`
+	} `json:"cvss"`
+}
+
+// isGHSAExport reports whether advisory JSON uses the GitHub REST API shape
+// Those documents carry "ghsa_id"; OSV documents carry "id" instead.
+func isGHSAExport(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+
+	var first map[string]json.RawMessage
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var list []map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &list); err != nil || len(list) == 0 {
+			return false
+		}
+		first = list[0]
+	} else if err := json.Unmarshal(trimmed, &first); err != nil {
+		return false
+	}
+
+	_, ok := first["ghsa_id"]
+	return ok
+}
+
+// decodeGHSAAdvisories decodes one advisory or an array of advisories
+func decodeGHSAAdvisories(data []byte) ([]ghsaAdvisory, error) {
+	trimmed := bytes.TrimSpace(data)
+
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var advisories []ghsaAdvisory
+		if err := json.Unmarshal(trimmed, &advisories); err != nil {
+			return nil, fmt.Errorf("failed to parse GitHub advisories: %w", err)
+		}
+		return advisories, nil
+	}
+
+	var advisory ghsaAdvisory
+	if err := json.Unmarshal(trimmed, &advisory); err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub advisory: %w", err)
+	}
+	return []ghsaAdvisory{advisory}, nil
+}
+
+// ghsaEntries converts GitHub advisories into blocklist entries
+func ghsaEntries(advisories []ghsaAdvisory) []BlocklistEntry {
+	entries := make([]BlocklistEntry, 0, len(advisories))
+
+	for _, advisory := range advisories {
+		// A withdrawn advisory was published in error and no longer applies
+		if advisory.WithdrawnAt != nil {
+			continue
+		}
+
+		identifiers := []string{advisory.GHSAID}
+		if advisory.CVEID != "" {
+			identifiers = appendUnique(identifiers, advisory.CVEID)
+		}
+		for _, id := range advisory.Identifiers {
+			identifiers = appendUnique(identifiers, id.Value)
+		}
+
+		severity := advisorySeverity(advisory.Severity)
+		if advisory.Severity == "" && advisory.CVSS.Score > 0 {
+			severity = cvssSeverity(advisory.CVSS.Score)
+		}
+
+		reason := strings.TrimSpace(advisory.Summary)
+		if reason == "" {
+			reason = "Listed in the GitHub Advisory Database"
+		}
+		reason = fmt.Sprintf("%s (%s)", reason, advisory.GHSAID)
+
+		references := make([]string, 0, len(advisory.References)+1)
+		if advisory.HTMLURL != "" {
+			references = append(references, advisory.HTMLURL)
+		}
+		references = appendUnique(references, advisory.References...)
+
+		for _, vuln := range advisory.Vulnerabilities {
+			if !strings.EqualFold(vuln.Package.Ecosystem, "npm") || vuln.Package.Name == "" {
+				continue
+			}
+
+			// Without a usable range we cannot tell which versions are affected;
+			// blocking every version would be worse than skipping the entry
+			exact, r, ok := parseGHSARange(vuln.VulnerableVersionRange)
+			if !ok {
+				continue
+			}
+
+			entry := BlocklistEntry{
+				PackageName: vuln.Package.Name,
+				Severity:    severity,
+				Reason:      reason,
+				Identifiers: identifiers,
+				CVSSScore:   advisory.CVSS.Score,
+				CVSSVector:  advisory.CVSS.VectorString,
+				References:  references,
+				Published:   advisory.PublishedAt,
+			}
+
+			if patched := ghsaPatchedVersion(vuln.FirstPatchedVersion); patched != "" {
+				entry.FixedIn = []string{patched}
+			}
+
+			if exact != "" {
+				entry.Version = exact
+			} else {
+				entry.Ranges = []VersionRange{r}
+			}
+
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// ghsaPatchedVersion reads first_patched_version, which is a plain string in the
+// global advisories API and {"identifier": "..."} in repository advisories
+func ghsaPatchedVersion(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var version string
+	if err := json.Unmarshal(raw, &version); err == nil {
+		return version
+	}
+
+	var object struct {
+		Identifier string `json:"identifier"`
+	}
+	if err := json.Unmarshal(raw, &object); err == nil {
+		return object.Identifier
+	}
+	return ""
+}
+
+// parseGHSARange parses a vulnerable_version_range such as ">= 1.0.0, < 1.2.3"
+// A single "= x" constraint is returned as an exact version instead of a range.
+// "> x" becomes an inclusive bound on the next version after x. ok is false
+// when the spec is empty or has a constraint we cannot parse.
+func parseGHSARange(spec string) (exact string, r VersionRange, ok bool) {
+	if strings.TrimSpace(spec) == "" {
+		return "", VersionRange{}, false
+	}
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+
+		version := strings.TrimSpace(strings.TrimLeft(part, "<>="))
+		op := strings.TrimSpace(strings.TrimSuffix(part, version))
+		v, err := semver.Parse(version)
+		if err != nil {
+			return "", VersionRange{}, false
+		}
+
+		switch op {
+		case ">=":
+			r.Introduced = version
+		case "<=":
+			r.LastAffected = version
+		case ">":
+			r.Introduced = nextVersion(v)
+		case "<":
+			r.Fixed = version
+		case "=":
+			exact = version
+		default:
+			return "", VersionRange{}, false
+		}
+	}
+
+	if r.Introduced == "" {
+		r.Introduced = "0"
+	}
+	return exact, r, true
+}
+
+// nextVersion returns the lowest version above v
+// Above 1.2.3 that is 1.2.4-0; above a pre-release like 1.2.3-beta it is 1.2.3-beta.0.
+func nextVersion(v semver.Version) string {
+	v.Build = ""
+	if len(v.Prerelease) > 0 {
+		v.Prerelease = append(v.Prerelease[:len(v.Prerelease):len(v.Prerelease)], "0")
+	} else {
+		v.Patch++
+		v.Prerelease = []string{"0"}
+	}
+	return v.String()
+}
+
+// cvssSeverity buckets a CVSS v3 base score into our severity levels
+func cvssSeverity(score float64) Severity {
+	switch {
+	case score >= 9.0:
+		return SeverityCritical
+	case score >= 7.0:
+		return SeverityHigh
+	case score >= 4.0:
+		return SeverityMedium
+	case score > 0:
+		return SeverityLow
+	default:
+		return SeverityInfo
+	}
+}
@@ -0,0 +1,116 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadBlocklist_GHSAExport(t *testing.T) {
+	// Arrange - a GitHub REST API export with a range and an exact version
+	path := "../../testdata/ghsa/advisories.json"
+
+	// Act
+	blocklist, err := LoadBlocklist(path)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, blocklist.Entries, 2, "the pip package is ignored")
+
+	lodash := blocklist.IsBlocked("lodash", "4.17.19")
+	require.NotNil(t, lodash)
+	assert.Equal(t, SeverityHigh, lodash.Severity)
+	assert.Equal(t, []string{"GHSA-p6mc-m468-83gw", "CVE-2020-8203"}, lodash.Identifiers)
+	assert.Equal(t, 7.4, lodash.CVSSScore)
+	assert.Equal(t, []string{"4.17.20"}, lodash.FixedIn)
+	assert.Contains(t, lodash.Reason, "Prototype Pollution")
+	assert.Nil(t, blocklist.IsBlocked("lodash", "4.17.20"))
+
+	echo := blocklist.IsBlocked("02-echo", "0.0.7")
+	require.NotNil(t, echo)
+	assert.Equal(t, "0.0.7", echo.Version)
+	assert.Equal(t, SeverityCritical, echo.Severity, "severity falls back to the CVSS score")
+	assert.Equal(t, []string{"0.0.8"}, echo.FixedIn, "object form of first_patched_version")
+	assert.Empty(t, echo.CVE())
+}
+
+func TestParseGHSARange(t *testing.T) {
+	tests := []struct {
+		spec          string
+		expectedExact string
+		expectedRange VersionRange
+	}{
+		{">= 1.0.0, < 1.2.3", "", VersionRange{Introduced: "1.0.0", Fixed: "1.2.3"}},
+		{"< 4.17.20", "", VersionRange{Introduced: "0", Fixed: "4.17.20"}},
+		{"<= 2.0.0", "", VersionRange{Introduced: "0", LastAffected: "2.0.0"}},
+		{"= 0.0.7", "0.0.7", VersionRange{Introduced: "0"}},
+		{"> 1.2.3, < 2.0.0", "", VersionRange{Introduced: "1.2.4-0", Fixed: "2.0.0"}},
+		{"> 1.0.0-beta", "", VersionRange{Introduced: "1.0.0-beta.0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			exact, r, ok := parseGHSARange(tt.spec)
+			require.True(t, ok)
+			assert.Equal(t, tt.expectedExact, exact)
+			assert.Equal(t, tt.expectedRange, r)
+		})
+	}
+}
+
+func TestParseGHSARange_ExclusiveLowerBound(t *testing.T) {
+	_, r, ok := parseGHSARange("> 1.2.3")
+	require.True(t, ok)
+	entry := BlocklistEntry{PackageName: "pkg", Ranges: []VersionRange{r}}
+
+	assert.False(t, entry.Matches("1.2.3"), "the bound itself is not affected")
+	assert.True(t, entry.Matches("1.2.4-alpha"))
+	assert.True(t, entry.Matches("1.2.4"))
+}
+
+func TestParseGHSARange_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "  ", "latest", ">= 1.0.0, < next", "~ 1.0.0"} {
+		t.Run(spec, func(t *testing.T) {
+			_, _, ok := parseGHSARange(spec)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestGHSAEntries_Skipped(t *testing.T) {
+	// Arrange - a withdrawn advisory and a vulnerability without a range
+	data := []byte(`[
+		{"ghsa_id": "GHSA-aaaa", "severity": "high", "withdrawn_at": "2024-01-02T00:00:00Z",
+		 "vulnerabilities": [{"package": {"ecosystem": "npm", "name": "withdrawn"}, "vulnerable_version_range": "< 2.0.0"}]},
+		{"ghsa_id": "GHSA-bbbb", "severity": "high", "withdrawn_at": null,
+		 "vulnerabilities": [
+			{"package": {"ecosystem": "npm", "name": "no-range"}, "vulnerable_version_range": ""},
+			{"package": {"ecosystem": "npm", "name": "ranged"}, "vulnerable_version_range": "< 2.0.0"}
+		 ]}
+	]`)
+	advisories, err := decodeGHSAAdvisories(data)
+	require.NoError(t, err)
+
+	// Act
+	entries := ghsaEntries(advisories)
+
+	// Assert
+	require.Len(t, entries, 1)
+	assert.Equal(t, "ranged", entries[0].PackageName)
+}
+
+func TestIsGHSAExport(t *testing.T) {
+	assert.True(t, isGHSAExport([]byte(`{"ghsa_id": "GHSA-1"}`)))
+	assert.True(t, isGHSAExport([]byte(`[{"ghsa_id": "GHSA-1"}]`)))
+	assert.False(t, isGHSAExport([]byte(`{"id": "GHSA-1", "affected": []}`)))
+	assert.False(t, isGHSAExport([]byte(`[]`)))
+}
+
+func TestCVSSSeverity(t *testing.T) {
+	assert.Equal(t, SeverityCritical, cvssSeverity(9.8))
+	assert.Equal(t, SeverityHigh, cvssSeverity(7.0))
+	assert.Equal(t, SeverityMedium, cvssSeverity(5.3))
+	assert.Equal(t, SeverityLow, cvssSeverity(0.1))
+	assert.Equal(t, SeverityInfo, cvssSeverity(0))
+}
@@ -0,0 +1,159 @@
+package scanner
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// gitFetcher reads a blocklist file from a git repository with the git CLI
+// Locations look like "git+https://host/org/repo.git#path/to/list.csv@ref";
+// the ref (branch, tag or commit) defaults to the remote HEAD. Only the one
+// commit is fetched (--depth 1), and its hash serves as the cache validator.
+type gitFetcher struct {
+	opts HTTPOptions // Proxy and TLS settings, passed on as git config
+}
+
+// isGitLocation reports whether a location is a git source
+func isGitLocation(location string) bool {
+	return strings.HasPrefix(location, "git+")
+}
+
+// parseGitLocation splits "git+<repo>#<path>[@<ref>]"
+func parseGitLocation(location string) (repo, path, ref string, err error) {
+	rest := strings.TrimPrefix(location, "git+")
+	i := strings.LastIndex(rest, "#")
+	if i < 0 {
+		return "", "", "", fmt.Errorf("git source %s needs the file to read: git+<repo>#<path>[@<ref>]", location)
+	}
+	repo, path = rest[:i], rest[i+1:]
+
+	if j := strings.LastIndex(path, "@"); j >= 0 {
+		path, ref = path[:j], path[j+1:]
+	}
+	path = strings.TrimPrefix(path, "/")
+	if repo == "" || path == "" {
+		return "", "", "", fmt.Errorf("git source %s needs a repository and a path", location)
+	}
+	if ref == "" {
+		ref = "HEAD"
+	}
+	if strings.HasPrefix(repo, "-") || strings.HasPrefix(ref, "-") {
+		return "", "", "", fmt.Errorf("git source %s: repository and ref must not start with \"-\"", location)
+	}
+	return repo, path, ref, nil
+}
+
+// fetchIfChanged fetches the ref and reads the file (and its signature, if
+// committed next to it); an unchanged commit hash means nothing changed
+func (f *gitFetcher) fetchIfChanged(location string, auth *Auth, previous cacheMeta) (*fetchResult, error) {
+	repo, path, ref, err := parseGitLocation(location)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := f.config(auth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch blocklist: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "   Fetching from git: %s (%s@%s)\n", repo, path, ref)
+
+	// Ask for the ref's commit first: if it is the cached one, skip the fetch
+	if previous.ETag != "" {
+		if out, err := runGit("", config, "ls-remote", repo, ref); err == nil {
+			if fields := strings.Fields(string(out)); len(fields) > 0 && fields[0] == previous.ETag {
+				return &fetchResult{ETag: previous.ETag, NotModified: true}, nil
+			}
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "hulud-scan-git-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	if _, err := runGit(dir, nil, "init", "-q"); err != nil {
+		return nil, fmt.Errorf("failed to fetch blocklist: %w", err)
+	}
+	if _, err := runGit(dir, config, "fetch", "-q", "--depth", "1", repo, ref); err != nil {
+		return nil, fmt.Errorf("failed to fetch blocklist from %s@%s: %w", repo, ref, err)
+	}
+
+	commit, err := runGit(dir, nil, "rev-parse", "FETCH_HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch blocklist: %w", err)
+	}
+
+	data, err := runGit(dir, nil, "show", "FETCH_HEAD:"+path)
+	if err != nil {
+		return nil, fmt.Errorf("%s not found at %s in %s", path, ref, repo)
+	}
+
+	result := &fetchResult{Data: data, ETag: strings.TrimSpace(string(commit))}
+	for _, suffix := range signatureSuffixes {
+		if sig, err := runGit(dir, nil, "show", "FETCH_HEAD:"+path+suffix); err == nil {
+			result.Signature = sig
+			break
+		}
+	}
+	return result, nil
+}
+
+// fetchSignature returns nil: signatures come with the commit in fetchIfChanged
+func (f *gitFetcher) fetchSignature(string, *Auth) []byte {
+	return nil
+}
+
+// config turns credentials, proxy and TLS settings into git config ("key=value")
+func (f *gitFetcher) config(auth *Auth) ([]string, error) {
+	var config []string
+
+	authorization, err := auth.header()
+	if err != nil {
+		return nil, err
+	}
+	if authorization != "" {
+		config = append(config, "http.extraHeader=Authorization: "+authorization)
+	}
+	if f.opts.Proxy != "" {
+		config = append(config, "http.proxy="+f.opts.Proxy)
+	}
+	if f.opts.CACert != "" {
+		config = append(config, "http.sslCAInfo="+f.opts.CACert)
+	}
+	if f.opts.ClientCert != "" {
+		config = append(config, "http.sslCert="+f.opts.ClientCert, "http.sslKey="+f.opts.ClientKey)
+	}
+	return config, nil
+}
+
+// runGit runs a git command (in dir, if set) and returns its standard output
+// The config is passed through the environment rather than "-c" so tokens do
+// not show up in the process list, and prompts are disabled so a missing
+// credential fails instead of hanging.
+func runGit(dir string, config []string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", fmt.Sprintf("GIT_CONFIG_COUNT=%d", len(config)))
+	for i, option := range config {
+		key, value, _ := strings.Cut(option, "=")
+		cmd.Env = append(cmd.Env, fmt.Sprintf("GIT_CONFIG_KEY_%d=%s", i, key), fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", i, value))
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("git %s: %s", args[0], message)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
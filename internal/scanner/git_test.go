@@ -0,0 +1,147 @@
+package scanner
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/signature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gitRepo creates a repository with one commit and returns a function that commits a file
+func gitRepo(t *testing.T) (dir string, commit func(path, content string)) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir = t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	run("init", "-q", "-b", "main")
+
+	return dir, func(path, content string) {
+		t.Helper()
+		full := filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+		run("add", path)
+		run("commit", "-q", "-m", "update "+path)
+	}
+}
+
+func TestParseGitLocation(t *testing.T) {
+	tests := []struct {
+		location    string
+		repo        string
+		path        string
+		ref         string
+		expectedErr string
+	}{
+		{"git+https://example.com/org/iocs.git#lists/npm.csv@v2", "https://example.com/org/iocs.git", "lists/npm.csv", "v2", ""},
+		{"git+https://example.com/org/iocs.git#/npm.yaml", "https://example.com/org/iocs.git", "npm.yaml", "HEAD", ""},
+		{"git+ssh://git@example.com/org/iocs.git#npm.csv@main", "ssh://git@example.com/org/iocs.git", "npm.csv", "main", ""},
+		{"git+https://example.com/org/iocs.git", "", "", "", "needs the file to read"},
+		{"git+https://example.com/org/iocs.git#@main", "", "", "", "needs a repository and a path"},
+		{"git+--upload-pack=evil#npm.csv", "", "", "", "must not start with"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.location, func(t *testing.T) {
+			repo, path, ref, err := parseGitLocation(tt.location)
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.repo, repo)
+			assert.Equal(t, tt.path, path)
+			assert.Equal(t, tt.ref, ref)
+		})
+	}
+}
+
+func TestLoader_GitSource(t *testing.T) {
+	// Arrange
+	dir, commit := gitRepo(t)
+	commit("lists/npm.csv", "Package,Version\nevil,= 1.0.0\n")
+	location := "git+file://" + dir + "#lists/npm.csv@main"
+	cacheDir := t.TempDir()
+	loader := &Loader{CacheDir: cacheDir}
+
+	// Act - first load fetches and caches
+	first, err := loader.Load(location)
+	require.NoError(t, err)
+	meta, ok := readCacheMeta(location, cacheDir)
+	require.True(t, ok)
+
+	// Act - an expired copy of an unchanged ref is reused
+	expire := func() {
+		old := time.Now().Add(-2 * time.Hour)
+		require.NoError(t, os.Chtimes(getCachePath(location, cacheDir), old, old))
+	}
+	expire()
+	_, err = loader.Load(location)
+	require.NoError(t, err)
+	unchanged, _ := readCacheMeta(location, cacheDir)
+
+	// Act - a new commit is picked up
+	commit("lists/npm.csv", "Package,Version\nevil,= 1.0.0\nworse,= 2.0.0\n")
+	expire()
+	updated, err := loader.Load(location)
+	require.NoError(t, err)
+	changed, _ := readCacheMeta(location, cacheDir)
+
+	// Assert
+	assert.Len(t, first.Entries, 1)
+	assert.Len(t, meta.ETag, 40, "the commit hash is the validator")
+	assert.Equal(t, meta.ETag, unchanged.ETag)
+	assert.Len(t, updated.Entries, 2)
+	assert.NotEqual(t, meta.ETag, changed.ETag)
+}
+
+func TestLoader_GitSourceFallsBackToCache(t *testing.T) {
+	// Arrange - cache a copy, then make the repository unreachable
+	dir, commit := gitRepo(t)
+	commit("npm.csv", "Package,Version\nevil,= 1.0.0\n")
+	location := "git+file://" + dir + "#npm.csv"
+	loader := &Loader{CacheDir: t.TempDir(), TTL: time.Nanosecond}
+
+	_, err := loader.Load(location)
+	require.NoError(t, err)
+	require.NoError(t, os.RemoveAll(dir))
+
+	// Act
+	blocklist, err := loader.Load(location)
+
+	// Assert
+	require.NoError(t, err)
+	assert.NotNil(t, blocklist.IsBlocked("evil", "1.0.0"))
+}
+
+func TestLoader_GitSourceSignature(t *testing.T) {
+	// Arrange - the signature is committed next to the list
+	priv, key := testSigner(t)
+	dir, commit := gitRepo(t)
+	commit("npm.csv", string(signedCSV))
+	commit("npm.csv.sig", string(sign(priv, signedCSV)))
+	loader := &Loader{CacheDir: t.TempDir(), TrustedKeys: []signature.PublicKey{key}, RequireSigned: true}
+
+	// Act
+	_, err := loader.Load("git+file://" + dir + "#npm.csv")
+	_, missingErr := loader.Load("git+file://" + dir + "#npm.csv.sig")
+
+	// Assert
+	require.NoError(t, err)
+	require.Error(t, missingErr, "the signature file itself is unsigned")
+}
@@ -0,0 +1,301 @@
+package scanner
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Defaults for HTTPOptions
+const (
+	defaultHTTPTimeout = 30 * time.Second
+	defaultRetries     = 2
+	defaultRetryWait   = 500 * time.Millisecond
+	maxRetryWait       = 30 * time.Second
+)
+
+// HTTPOptions configures how blocklists are downloaded
+// The zero value uses HTTPS_PROXY/HTTP_PROXY/NO_PROXY from the environment,
+// the system CA pool, a 30s timeout and two retries.
+//
+// In the config file these live under "http":
+//
+//	http:
+//	  proxy: http://proxy.corp.example:3128
+//	  ca_cert: /etc/ssl/corp-ca.pem
+//	  client_cert: /etc/hulud-scan/client.crt
+//	  client_key: /etc/hulud-scan/client.key
+//	  retries: 4
+type HTTPOptions struct {
+	Proxy      string        `yaml:"proxy"`       // Proxy URL (overrides the environment)
+	CACert     string        `yaml:"ca_cert"`     // PEM bundle trusted in addition to the system CAs
+	ClientCert string        `yaml:"client_cert"` // PEM client certificate for mutual TLS
+	ClientKey  string        `yaml:"client_key"`  // PEM private key of ClientCert
+	Timeout    time.Duration `yaml:"timeout"`     // Per-attempt timeout (0 means 30s)
+	Retries    int           `yaml:"retries"`     // Extra attempts after a failure (0 means 2, negative disables)
+	RetryWait  time.Duration `yaml:"retry_wait"`  // First backoff delay, doubled per attempt (0 means 500ms)
+}
+
+// Auth holds the credentials for one source
+// A bearer token wins over basic auth. Secrets can be read from environment
+// variables so they stay out of the config file:
+//
+//	auth:
+//	  token_env: INTERNAL_BLOCKLIST_TOKEN
+type Auth struct {
+	Token       string `yaml:"token"`        // Bearer token
+	TokenEnv    string `yaml:"token_env"`    // Environment variable holding the bearer token
+	Username    string `yaml:"username"`     // Basic auth user
+	Password    string `yaml:"password"`     // Basic auth password
+	PasswordEnv string `yaml:"password_env"` // Environment variable holding the password
+}
+
+// header returns the Authorization header value ("" without credentials)
+func (a *Auth) header() (string, error) {
+	if a == nil {
+		return "", nil
+	}
+
+	token, err := secret(a.Token, a.TokenEnv)
+	if err != nil {
+		return "", err
+	}
+	if token != "" {
+		return "Bearer " + token, nil
+	}
+
+	if a.Username != "" {
+		password, err := secret(a.Password, a.PasswordEnv)
+		if err != nil {
+			return "", err
+		}
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(a.Username+":"+password)), nil
+	}
+	return "", nil
+}
+
+// secret returns an inline value or the value of an environment variable
+func secret(value, env string) (string, error) {
+	if value != "" || env == "" {
+		return value, nil
+	}
+	value = os.Getenv(env)
+	if value == "" {
+		return "", fmt.Errorf("environment variable %s is not set", env)
+	}
+	return value, nil
+}
+
+// httpFetcher downloads blocklists with retries
+type httpFetcher struct {
+	client    *http.Client
+	retries   int
+	retryWait time.Duration
+}
+
+// newHTTPFetcher builds a fetcher (and its transport) from the options
+func newHTTPFetcher(opts HTTPOptions) (*httpFetcher, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if opts.Proxy != "" {
+		proxyURL, err := url.Parse(opts.Proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", opts.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if opts.CACert != "" || opts.ClientCert != "" {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+		if opts.CACert != "" {
+			pem, err := os.ReadFile(opts.CACert)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+			}
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("CA bundle %s contains no PEM certificates", opts.CACert)
+			}
+			tlsConfig.RootCAs = pool
+		}
+
+		if opts.ClientCert != "" {
+			if opts.ClientKey == "" {
+				return nil, fmt.Errorf("client_cert needs a client_key")
+			}
+			cert, err := tls.LoadX509KeyPair(opts.ClientCert, opts.ClientKey)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load client certificate: %w", err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	fetcher := &httpFetcher{
+		client:    &http.Client{Transport: transport, Timeout: opts.Timeout},
+		retries:   opts.Retries,
+		retryWait: opts.RetryWait,
+	}
+	if fetcher.client.Timeout <= 0 {
+		fetcher.client.Timeout = defaultHTTPTimeout
+	}
+	if fetcher.retries == 0 {
+		fetcher.retries = defaultRetries
+	} else if fetcher.retries < 0 {
+		fetcher.retries = 0
+	}
+	if fetcher.retryWait <= 0 {
+		fetcher.retryWait = defaultRetryWait
+	}
+	return fetcher, nil
+}
+
+// defaultFetcher is used by the package-level download helpers
+var defaultFetcher = &httpFetcher{
+	client:    &http.Client{Timeout: defaultHTTPTimeout},
+	retries:   defaultRetries,
+	retryWait: defaultRetryWait,
+}
+
+// blocklistFetcher downloads remote blocklists of one kind (HTTP, git, OCI)
+type blocklistFetcher interface {
+	// fetchIfChanged downloads location unless it still matches previous
+	fetchIfChanged(location string, auth *Auth, previous cacheMeta) (*fetchResult, error)
+	// fetchSignature downloads a detached signature that fetchIfChanged did not bring along
+	fetchSignature(location string, auth *Auth) []byte
+}
+
+// fetchIfChanged downloads a URL, sending If-None-Match / If-Modified-Since
+// when the previous response's validators are known
+func (f *httpFetcher) fetchIfChanged(url string, auth *Auth, previous cacheMeta) (*fetchResult, error) {
+	// Convert GitHub web URL to raw URL if needed
+	url = convertToRawURL(url)
+
+	fmt.Fprintf(os.Stderr, "   Downloading from: %s\n", url)
+
+	authorization, err := auth.header()
+	if err != nil {
+		return nil, fmt.Errorf("failed to download blocklist: %w", err)
+	}
+
+	headers := http.Header{}
+	if authorization != "" {
+		headers.Set("Authorization", authorization)
+	}
+	if previous.ETag != "" {
+		headers.Set("If-None-Match", previous.ETag)
+	}
+	if previous.LastModified != "" {
+		headers.Set("If-Modified-Since", previous.LastModified)
+	}
+
+	// Download the blocklist
+	resp, body, err := f.get(url, headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download blocklist: %w", err)
+	}
+
+	result := &fetchResult{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		result.NotModified = true
+		return result, nil
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("failed to download blocklist: HTTP %d (check the source's auth settings)", resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("failed to download blocklist: HTTP %d", resp.StatusCode)
+	}
+
+	result.Data = body
+	return result, nil
+}
+
+// fetchSignature downloads the detached signature for a URL, or returns nil if there is none
+func (f *httpFetcher) fetchSignature(url string, auth *Auth) []byte {
+	for _, suffix := range signatureSuffixes {
+		if result, err := f.fetchIfChanged(url+suffix, auth, cacheMeta{}); err == nil {
+			return result.Data
+		}
+	}
+	return nil
+}
+
+// get sends a GET request and reads the whole response
+// Network errors, 429 and 5xx responses are retried with exponential backoff
+// (honouring Retry-After); any other response is returned for the caller to judge.
+func (f *httpFetcher) get(url string, headers http.Header) (*http.Response, []byte, error) {
+	wait := f.retryWait
+	for attempt := 0; ; attempt++ {
+		resp, body, retryAfter, err := f.getOnce(url, headers)
+		if err == nil && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+			return resp, body, nil
+		}
+		if err == nil {
+			err = fmt.Errorf("HTTP %d", resp.StatusCode)
+		}
+		if attempt >= f.retries {
+			return resp, body, err
+		}
+
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+		if wait > maxRetryWait {
+			wait = maxRetryWait
+		}
+		fmt.Fprintf(os.Stderr, "   ⚠️  %v, retrying in %s\n", err, wait)
+		time.Sleep(wait)
+		wait *= 2
+	}
+}
+
+// getOnce makes a single request; retryAfter is the server's Retry-After, if any
+func (f *httpFetcher) getOnce(url string, headers http.Header) (resp *http.Response, body []byte, retryAfter time.Duration, err error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	req.Header = headers.Clone()
+
+	resp, err = f.client.Do(req)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to close response body: %v\n", closeErr)
+		}
+	}()
+
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to read response: %w", err)
+	}
+	return resp, body, parseRetryAfter(resp.Header.Get("Retry-After")), nil
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds (0 if absent)
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,258 @@
+package scanner
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCSV = "Package,Version\nevil,= 1.0.0\n"
+
+// writePEM writes one PEM block to a temp file and returns its path
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600))
+	return path
+}
+
+// clientCertificate creates a self-signed client certificate and its key files
+func clientCertificate(t *testing.T) (cert *x509.Certificate, certPath, keyPath string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "hulud-scan test client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err = x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return cert, writePEM(t, "client.crt", "CERTIFICATE", der), writePEM(t, "client.key", "PRIVATE KEY", keyDER)
+}
+
+func TestHTTPFetcher_Auth(t *testing.T) {
+	t.Setenv("HULUD_TEST_TOKEN", "s3cret")
+	t.Setenv("HULUD_TEST_PASSWORD", "hunter2")
+
+	tests := []struct {
+		name        string
+		auth        *Auth
+		expected    string
+		expectedErr string
+	}{
+		{"none", nil, "", ""},
+		{"inline bearer", &Auth{Token: "abc"}, "Bearer abc", ""},
+		{"bearer from env", &Auth{TokenEnv: "HULUD_TEST_TOKEN"}, "Bearer s3cret", ""},
+		{"basic from env", &Auth{Username: "ci", PasswordEnv: "HULUD_TEST_PASSWORD"}, "Basic Y2k6aHVudGVyMg==", ""},
+		{"unset env", &Auth{TokenEnv: "HULUD_TEST_UNSET"}, "", "HULUD_TEST_UNSET is not set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r.Header.Get("Authorization")
+				_, _ = w.Write([]byte(testCSV))
+			}))
+			defer server.Close()
+
+			fetcher, err := newHTTPFetcher(HTTPOptions{})
+			require.NoError(t, err)
+
+			_, err = fetcher.fetchIfChanged(server.URL, tt.auth, cacheMeta{})
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, received)
+		})
+	}
+}
+
+func TestHTTPFetcher_Retries(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		retries  int
+		attempts int
+		ok       bool
+	}{
+		{"recovers from 503", []int{503, 503, 200}, 2, 3, true},
+		{"gives up", []int{500, 500, 500, 500}, 2, 3, false},
+		{"429 is retried", []int{429, 200}, 2, 2, true},
+		{"404 is not retried", []int{404, 200}, 2, 1, false},
+		{"401 is not retried", []int{401, 200}, 2, 1, false},
+		{"retries disabled", []int{503, 200}, -1, 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[attempts]
+				attempts++
+				w.WriteHeader(status)
+				if status == http.StatusOK {
+					_, _ = w.Write([]byte(testCSV))
+				}
+			}))
+			defer server.Close()
+
+			fetcher, err := newHTTPFetcher(HTTPOptions{Retries: tt.retries, RetryWait: time.Millisecond})
+			require.NoError(t, err)
+
+			result, err := fetcher.fetchIfChanged(server.URL, nil, cacheMeta{})
+
+			assert.Equal(t, tt.attempts, attempts)
+			if tt.ok {
+				require.NoError(t, err)
+				assert.Equal(t, testCSV, string(result.Data))
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestHTTPFetcher_CustomCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testCSV))
+	}))
+	defer server.Close()
+
+	// Without the CA the server's certificate is not trusted
+	plain, err := newHTTPFetcher(HTTPOptions{Retries: -1})
+	require.NoError(t, err)
+	_, err = plain.fetchIfChanged(server.URL, nil, cacheMeta{})
+	require.Error(t, err)
+
+	caPath := writePEM(t, "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+	fetcher, err := newHTTPFetcher(HTTPOptions{CACert: caPath})
+	require.NoError(t, err)
+
+	result, err := fetcher.fetchIfChanged(server.URL, nil, cacheMeta{})
+
+	require.NoError(t, err)
+	assert.Equal(t, testCSV, string(result.Data))
+}
+
+func TestHTTPFetcher_ClientCertificate(t *testing.T) {
+	// Arrange - a server that only talks to our client certificate
+	cert, certPath, keyPath := clientCertificate(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(cert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testCSV))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+	caPath := writePEM(t, "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+
+	// Act
+	without, err := newHTTPFetcher(HTTPOptions{CACert: caPath, Retries: -1})
+	require.NoError(t, err)
+	_, withoutErr := without.fetchIfChanged(server.URL, nil, cacheMeta{})
+
+	with, err := newHTTPFetcher(HTTPOptions{CACert: caPath, ClientCert: certPath, ClientKey: keyPath})
+	require.NoError(t, err)
+	result, err := with.fetchIfChanged(server.URL, nil, cacheMeta{})
+
+	// Assert
+	assert.Error(t, withoutErr, "the server requires a client certificate")
+	require.NoError(t, err)
+	assert.Equal(t, testCSV, string(result.Data))
+}
+
+func TestHTTPFetcher_Proxy(t *testing.T) {
+	// The proxy answers for any host, so the target never needs to resolve
+	var requested string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.String()
+		_, _ = w.Write([]byte(testCSV))
+	}))
+	defer proxy.Close()
+
+	fetcher, err := newHTTPFetcher(HTTPOptions{Proxy: proxy.URL})
+	require.NoError(t, err)
+
+	result, err := fetcher.fetchIfChanged("http://blocklists.internal.example/list.csv", nil, cacheMeta{})
+
+	require.NoError(t, err)
+	assert.Equal(t, "http://blocklists.internal.example/list.csv", requested)
+	assert.Equal(t, testCSV, string(result.Data))
+}
+
+func TestNewHTTPFetcher_Errors(t *testing.T) {
+	notPEM := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0600))
+
+	tests := []struct {
+		name        string
+		opts        HTTPOptions
+		expectedErr string
+	}{
+		{"bad proxy", HTTPOptions{Proxy: "::not a url"}, "invalid proxy URL"},
+		{"missing CA", HTTPOptions{CACert: "missing.pem"}, "failed to read CA bundle"},
+		{"CA without certificates", HTTPOptions{CACert: notPEM}, "contains no PEM certificates"},
+		{"cert without key", HTTPOptions{ClientCert: "client.crt"}, "needs a client_key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newHTTPFetcher(tt.opts)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
+
+func TestLoader_SourceAuth(t *testing.T) {
+	// Arrange - a protected list, loaded through the normal Loader path
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer internal" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(testCSV))
+	}))
+	defer server.Close()
+	loader := &Loader{CacheDir: t.TempDir()}
+
+	// Act
+	_, anonymousErr := loader.LoadSource(Source{Location: server.URL + "/anonymous.csv"})
+	blocklist, err := loader.LoadSource(Source{Location: server.URL + "/list.csv", Auth: &Auth{Token: "internal"}})
+
+	// Assert
+	require.Error(t, anonymousErr)
+	assert.Contains(t, anonymousErr.Error(), "HTTP 401")
+	require.NoError(t, err)
+	assert.NotNil(t, blocklist.IsBlocked("evil", "1.0.0"))
+}
@@ -0,0 +1,354 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/signature"
+)
+
+// signatureSuffixes are tried, in order, to find a detached signature next to a blocklist
+var signatureSuffixes = []string{".minisig", ".sig"}
+
+// ErrOffline is returned when a download is needed but network access is disabled
+var ErrOffline = errors.New("network access is disabled (offline mode)")
+
+// Loader loads blocklists from URLs and local paths with shared settings
+// The zero value loads without a cache and without signature checks.
+//
+// With TrustedKeys set, a blocklist that has a detached signature
+// (<location>.minisig or <location>.sig) must verify against one of the keys;
+// RequireSigned additionally refuses blocklists without a signature. Cached
+// copies are checked again every time they are used.
+//
+// Cached copies are used for TTL (or a source's own TTL); after that the
+// server is asked whether the list changed, so an unchanged list is not
+// downloaded again. Offline never touches the network: remote sources must
+// already be cached (at any age) and stale copies are only reported.
+//
+// Downloads go through HTTP (proxy, CA bundle, client certificate, retries)
+// and send the credentials of the source's Auth.
+type Loader struct {
+	CacheDir      string                // Cache directory for downloads ("" disables caching)
+	TTL           time.Duration         // Default cache lifetime (0 means one hour)
+	TrustedKeys   []signature.PublicKey // Keys accepted for detached signatures
+	RequireSigned bool                  // Refuse blocklists without a valid signature
+	Offline       bool                  // Use only local files and cached copies
+	HTTP          HTTPOptions           // Proxy, TLS, timeout and retry settings for downloads
+}
+
+// LoadAll loads every source concurrently and merges them into one blocklist
+// All sources are required: if any of them fails, the whole load fails.
+func (l *Loader) LoadAll(sources []Source) (*Blocklist, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no blocklist sources configured")
+	}
+
+	lists := make([]*Blocklist, len(sources))
+	errs := make([]error, len(sources))
+
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		wg.Add(1)
+		go func(i int, source Source) {
+			defer wg.Done()
+			lists[i], errs[i] = l.LoadSource(source)
+		}(i, source)
+	}
+	wg.Wait()
+
+	// Report failures in source order so the message is deterministic
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("blocklist %s: %w", sources[i].Location, err)
+		}
+	}
+
+	return MergeBlocklists(lists...), nil
+}
+
+// Load loads one blocklist from a URL (through the cache) or a local path
+// Every returned entry records location as its source.
+func (l *Loader) Load(location string) (*Blocklist, error) {
+	return l.LoadSource(Source{Location: location})
+}
+
+// LoadSource is Load with the per-source settings of a configured source
+func (l *Loader) LoadSource(source Source) (*Blocklist, error) {
+	blocklist, err := l.load(source)
+	if err != nil {
+		return nil, err
+	}
+
+	source.applyDefaults(blocklist.Entries)
+	for i := range blocklist.Entries {
+		blocklist.Entries[i].Sources = []string{source.Location}
+	}
+	return blocklist, nil
+}
+
+// LoadRaw loads a source's entries as the list itself has them
+// Unlike LoadSource it fills in no default severity, reason or tags and records
+// no sources, so converting a list does not add claims the list never made.
+func (l *Loader) LoadRaw(source Source) (*Blocklist, error) {
+	return l.load(source)
+}
+
+// ttl returns how long a cached copy of source stays fresh
+func (l *Loader) ttl(source Source) time.Duration {
+	if source.TTL > 0 {
+		return source.TTL
+	}
+	if l.TTL > 0 {
+		return l.TTL
+	}
+	return cacheTTL
+}
+
+// load picks between the cache, a download and a local file
+func (l *Loader) load(source Source) (*Blocklist, error) {
+	if l.RequireSigned && len(l.TrustedKeys) == 0 {
+		return nil, fmt.Errorf("signed blocklists are required but no trusted keys are configured")
+	}
+
+	location := source.Location
+	if !IsRemote(location) {
+		return l.loadLocal(location)
+	}
+
+	if l.Offline {
+		return l.loadOffline(source)
+	}
+
+	// Check cache first
+	if l.CacheDir != "" {
+		if data, sig, err := readCache(location, l.CacheDir, l.ttl(source), false); err == nil {
+			if err := l.verify(location, data, sig); err != nil {
+				fmt.Fprintf(os.Stderr, "   ⚠️  Cached copy rejected (%v), downloading again\n", err)
+			} else {
+				fmt.Fprintf(os.Stderr, "   Using cached blocklist (%s old)\n", l.cacheAge(location))
+				return parseBlocklistData(location, data)
+			}
+		}
+	}
+
+	blocklist, err := l.refresh(source)
+	if err != nil {
+		// Try to use expired cache as fallback
+		if l.CacheDir != "" {
+			if data, sig, cacheErr := readCache(location, l.CacheDir, 0, true); cacheErr == nil &&
+				l.verify(location, data, sig) == nil {
+				fmt.Fprintf(os.Stderr, "   ⚠️  Download failed, using cached version (may be outdated)\n")
+				return parseBlocklistData(location, data)
+			}
+		}
+		return nil, err
+	}
+
+	return blocklist, nil
+}
+
+// loadOffline loads a remote source from the cache only, whatever its age
+func (l *Loader) loadOffline(source Source) (*Blocklist, error) {
+	location := source.Location
+	if l.CacheDir == "" {
+		return nil, fmt.Errorf("%s: %w and caching is disabled", location, ErrOffline)
+	}
+
+	data, sig, err := readCache(location, l.CacheDir, 0, true)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not cached and %w; import a cache with `hulud-scan cache import`", location, ErrOffline)
+	}
+	if err := l.verify(location, data, sig); err != nil {
+		return nil, err
+	}
+
+	age := l.cacheAge(location)
+	if cachedAt, ok := CachedAt(location, l.CacheDir); ok && time.Since(cachedAt) > l.ttl(source) {
+		fmt.Fprintf(os.Stderr, "   ⚠️  Offline: cached blocklist is %s old (TTL %s) and may be outdated\n", age, l.ttl(source))
+	} else {
+		fmt.Fprintf(os.Stderr, "   Offline: using cached blocklist (%s old)\n", age)
+	}
+	return parseBlocklistData(location, data)
+}
+
+// cacheAge formats the age of a cached copy for status messages
+func (l *Loader) cacheAge(url string) string {
+	cachedAt, ok := CachedAt(url, l.CacheDir)
+	if !ok {
+		return "unknown"
+	}
+	return time.Since(cachedAt).Round(time.Second).String()
+}
+
+// Refresh downloads a remote blocklist, bypassing a fresh cache, verifies it
+// and stores the new copy (with its signature) in the cache directory
+// When a copy is cached, the request is conditional: if the server answers
+// "304 Not Modified", the cached copy is re-verified and marked fresh instead.
+func (l *Loader) Refresh(source Source) (*Blocklist, error) {
+	blocklist, err := l.refresh(source)
+	if err != nil {
+		return nil, err
+	}
+	source.applyDefaults(blocklist.Entries)
+	return blocklist, nil
+}
+
+// refresh is Refresh without the source defaults, which LoadSource applies
+func (l *Loader) refresh(source Source) (*Blocklist, error) {
+	url := source.Location
+	if l.Offline {
+		return nil, fmt.Errorf("cannot refresh %s: %w", url, ErrOffline)
+	}
+
+	fetcher, err := l.fetcherFor(url)
+	if err != nil {
+		return nil, err
+	}
+
+	var previous cacheMeta
+	if l.CacheDir != "" {
+		previous, _ = readCacheMeta(url, l.CacheDir)
+	}
+
+	result, err := fetcher.fetchIfChanged(url, source.Auth, previous)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.NotModified {
+		if blocklist, err := l.reuseCache(url, previous, result); err == nil {
+			return blocklist, nil
+		}
+		// The cached copy is gone or no longer verifies: fetch it in full
+		if result, err = fetcher.fetchIfChanged(url, source.Auth, cacheMeta{}); err != nil {
+			return nil, err
+		}
+	}
+
+	sig := result.Signature
+	if sig == nil && len(l.TrustedKeys) > 0 {
+		sig = fetcher.fetchSignature(url, source.Auth)
+	}
+	if err := l.verify(url, result.Data, sig); err != nil {
+		return nil, err
+	}
+
+	blocklist, err := parseBlocklistData(url, result.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save to cache (the original bytes, so no format loses detail)
+	if l.CacheDir != "" {
+		meta := cacheMeta{URL: url, ETag: result.ETag, LastModified: result.LastModified, FetchedAt: time.Now().UTC()}
+		if err := saveToCache(url, l.CacheDir, result.Data, sig, meta); err != nil {
+			// Non-fatal - just log
+			fmt.Fprintf(os.Stderr, "   Warning: failed to cache blocklist: %v\n", err)
+		}
+	}
+
+	return blocklist, nil
+}
+
+// fetcherFor picks the fetcher for a remote location's scheme
+func (l *Loader) fetcherFor(location string) (blocklistFetcher, error) {
+	switch {
+	case isGitLocation(location):
+		return &gitFetcher{opts: l.HTTP}, nil
+	case isOCILocation(location):
+		fetcher, err := newHTTPFetcher(l.HTTP)
+		if err != nil {
+			return nil, err
+		}
+		return &ociFetcher{http: fetcher}, nil
+	default:
+		return newHTTPFetcher(l.HTTP)
+	}
+}
+
+// reuseCache loads the cached copy after a 304 response and marks it fresh
+func (l *Loader) reuseCache(url string, previous cacheMeta, result *fetchResult) (*Blocklist, error) {
+	data, sig, err := readCache(url, l.CacheDir, 0, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.verify(url, data, sig); err != nil {
+		return nil, err
+	}
+
+	blocklist, err := parseBlocklistData(url, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// A 304 may omit the validators; keep the ones we already have
+	meta := previous
+	meta.URL = url
+	meta.FetchedAt = time.Now().UTC()
+	if result.ETag != "" {
+		meta.ETag = result.ETag
+	}
+	if result.LastModified != "" {
+		meta.LastModified = result.LastModified
+	}
+	if err := touchCache(url, l.CacheDir, meta); err != nil {
+		fmt.Fprintf(os.Stderr, "   Warning: failed to refresh cache: %v\n", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "   Blocklist unchanged since last download, using cached copy\n")
+	return blocklist, nil
+}
+
+// loadLocal loads a local file (checking its signature) or an advisory directory
+func (l *Loader) loadLocal(path string) (*Blocklist, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blocklist file: %w", err)
+	}
+
+	if info.IsDir() {
+		if l.RequireSigned {
+			return nil, fmt.Errorf("%s is a directory; only single files can be signed", path)
+		}
+		return loadAdvisoryDir(path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blocklist file: %w", err)
+	}
+
+	var sig []byte
+	if len(l.TrustedKeys) > 0 {
+		for _, suffix := range signatureSuffixes {
+			if sig, err = os.ReadFile(path + suffix); err == nil {
+				break
+			}
+			sig = nil
+		}
+	}
+
+	if err := l.verify(path, data, sig); err != nil {
+		return nil, err
+	}
+	return parseBlocklistData(path, data)
+}
+
+// verify checks data against its detached signature (nil when there is none)
+func (l *Loader) verify(location string, data, sig []byte) error {
+	if sig == nil {
+		if l.RequireSigned {
+			return fmt.Errorf("%s has no signature (.minisig or .sig) and signed blocklists are required", location)
+		}
+		return nil
+	}
+
+	if err := signature.Verify(data, sig, l.TrustedKeys); err != nil {
+		return fmt.Errorf("signature check failed for %s: %w", location, err)
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package scanner
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/signature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var signedCSV = []byte("package_name,version,severity,reason,cve\nevil,1.0.0,critical,Worm,\n")
+
+// testSigner returns a private key and the matching trusted key
+func testSigner(t *testing.T) (ed25519.PrivateKey, signature.PublicKey) {
+	t.Helper()
+	priv := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{7}, ed25519.SeedSize))
+	key, err := signature.ParseKey(base64.StdEncoding.EncodeToString(priv.Public().(ed25519.PublicKey)))
+	require.NoError(t, err)
+	return priv, key
+}
+
+// sign produces a raw base64 ed25519 signature
+func sign(priv ed25519.PrivateKey, data []byte) []byte {
+	return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, data)))
+}
+
+// signedServer serves data at /list.csv and sig at /list.csv.sig (404 when sig is nil)
+func signedServer(data, sig []byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/list.csv":
+			_, _ = w.Write(data)
+		case "/list.csv.sig":
+			if sig == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(sig)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestLoader_SignedDownloadIsCachedWithSignature(t *testing.T) {
+	// Arrange
+	priv, key := testSigner(t)
+	server := signedServer(signedCSV, sign(priv, signedCSV))
+	defer server.Close()
+
+	loader := &Loader{CacheDir: t.TempDir(), TrustedKeys: []signature.PublicKey{key}, RequireSigned: true}
+	url := server.URL + "/list.csv"
+
+	// Act
+	blocklist, err := loader.Load(url)
+
+	// Assert
+	require.NoError(t, err)
+	assert.NotNil(t, blocklist.IsBlocked("evil", "1.0.0"))
+	assert.FileExists(t, getCachePath(url, loader.CacheDir)+".sig")
+
+	// The cached copy verifies too
+	_, err = loader.Load(url)
+	assert.NoError(t, err)
+}
+
+func TestLoader_RejectsTamperedDownload(t *testing.T) {
+	priv, key := testSigner(t)
+	tampered := append(append([]byte(nil), signedCSV...), []byte("left-pad,1.3.0,critical,Fake,\n")...)
+	server := signedServer(tampered, sign(priv, signedCSV))
+	defer server.Close()
+
+	loader := &Loader{TrustedKeys: []signature.PublicKey{key}}
+	_, err := loader.Load(server.URL + "/list.csv")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "signature check failed")
+}
+
+func TestLoader_RequireSignedRefusesUnsigned(t *testing.T) {
+	_, key := testSigner(t)
+	server := signedServer(signedCSV, nil)
+	defer server.Close()
+
+	loader := &Loader{TrustedKeys: []signature.PublicKey{key}, RequireSigned: true}
+	_, err := loader.Load(server.URL + "/list.csv")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "has no signature")
+}
+
+func TestLoader_TamperedCacheIsDownloadedAgain(t *testing.T) {
+	// Arrange - a cached copy that was modified after it was written
+	priv, key := testSigner(t)
+	server := signedServer(signedCSV, sign(priv, signedCSV))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	url := server.URL + "/list.csv"
+	poisoned := []byte("package_name,version,severity,reason,cve\nharmless,1.0.0,low,Decoy,\n")
+	require.NoError(t, saveToCache(url, cacheDir, poisoned, sign(priv, signedCSV), cacheMeta{URL: url}))
+
+	loader := &Loader{CacheDir: cacheDir, TrustedKeys: []signature.PublicKey{key}, RequireSigned: true}
+
+	// Act
+	blocklist, err := loader.Load(url)
+
+	// Assert - the fresh, verified download replaced the poisoned copy
+	require.NoError(t, err)
+	assert.NotNil(t, blocklist.IsBlocked("evil", "1.0.0"))
+	cached, err := os.ReadFile(getCachePath(url, cacheDir))
+	require.NoError(t, err)
+	assert.Equal(t, signedCSV, cached)
+}
+
+func TestLoader_LocalFiles(t *testing.T) {
+	priv, key := testSigner(t)
+	dir := t.TempDir()
+
+	signed := filepath.Join(dir, "signed.csv")
+	require.NoError(t, os.WriteFile(signed, signedCSV, 0644))
+	require.NoError(t, os.WriteFile(signed+".minisig", sign(priv, signedCSV), 0644))
+
+	unsigned := filepath.Join(dir, "unsigned.csv")
+	require.NoError(t, os.WriteFile(unsigned, signedCSV, 0644))
+
+	tests := []struct {
+		name        string
+		loader      Loader
+		path        string
+		expectedErr string
+	}{
+		{"signed file", Loader{TrustedKeys: []signature.PublicKey{key}, RequireSigned: true}, signed, ""},
+		{"unsigned file allowed", Loader{TrustedKeys: []signature.PublicKey{key}}, unsigned, ""},
+		{"unsigned file required", Loader{TrustedKeys: []signature.PublicKey{key}, RequireSigned: true}, unsigned, "has no signature"},
+		{"directory required", Loader{TrustedKeys: []signature.PublicKey{key}, RequireSigned: true}, "../../testdata/osv", "only single files can be signed"},
+		{"no trusted keys", Loader{RequireSigned: true}, signed, "no trusted keys are configured"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.loader.Load(tt.path)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestLoader_LoadRaw(t *testing.T) {
+	// Arrange - a Wiz-style list with neither severity nor reason
+	path := filepath.Join(t.TempDir(), "wiz.csv")
+	require.NoError(t, os.WriteFile(path, []byte("Package,Version\nevil,= 1.0.0\n"), 0644))
+	source := Source{Location: path, Severity: SeverityHigh, Tags: []string{"wiz"}}
+	loader := Loader{}
+
+	// Act
+	raw, err := loader.LoadRaw(source)
+	require.NoError(t, err)
+	loaded, err := loader.LoadSource(source)
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, raw.Entries, 1)
+	assert.Empty(t, raw.Entries[0].Severity)
+	assert.Empty(t, raw.Entries[0].Reason)
+	assert.Empty(t, raw.Entries[0].Tags)
+	assert.Empty(t, raw.Entries[0].Sources)
+	assert.Equal(t, SeverityHigh, loaded.Entries[0].Severity, "LoadSource still fills in the defaults")
+}
+
+func TestLoader_Offline(t *testing.T) {
+	// Arrange - any request to this server fails the test
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("offline loader made a request to %s", r.URL.Path)
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	cachedURL := server.URL + "/cached.csv"
+	require.NoError(t, saveToCache(cachedURL, cacheDir, signedCSV, nil, cacheMeta{URL: cachedURL}))
+	lastWeek := time.Now().Add(-7 * 24 * time.Hour)
+	require.NoError(t, os.Chtimes(getCachePath(cachedURL, cacheDir), lastWeek, lastWeek))
+
+	loader := &Loader{CacheDir: cacheDir, Offline: true}
+
+	// Act & Assert - a stale cached copy is still used
+	blocklist, err := loader.Load(cachedURL)
+	require.NoError(t, err)
+	assert.NotNil(t, blocklist.IsBlocked("evil", "1.0.0"))
+
+	// Local files still work
+	_, err = loader.Load("../../testdata/sample-blocklist.csv")
+	assert.NoError(t, err)
+
+	// Uncached sources fail clearly
+	_, err = loader.Load(server.URL + "/missing.csv")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrOffline)
+	assert.Contains(t, err.Error(), "is not cached")
+
+	// Refreshing is refused
+	_, err = loader.Refresh(Source{Location: cachedURL})
+	assert.ErrorIs(t, err, ErrOffline)
+}
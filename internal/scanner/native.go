@@ -0,0 +1,193 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NativeSchemaVersion is the newest version of the native blocklist schema we understand
+const NativeSchemaVersion = 1
+
+// nativeBlocklist is the native JSON/YAML blocklist document
+//
+//	schema_version: 1
+//	name: Shai-Hulud 2.0
+//	entries:
+//	  - package: "@ctrl/tinycolor"
+//	    versions: ["4.1.1", "4.1.2"]
+//	    severity: critical
+//	    reason: Worm payload in postinstall script
+//	    identifiers: [MAL-2025-50000]
+//	    fixed_in: ["4.1.3"]
+//	    references: [https://example.com/advisory]
+//	    published: 2025-09-15
+//	    hashes: ["sha256:46faab8ab153fae6e80e7cca38eab363075bb524edd79e42269217a083628f09"]
+//	    tags: [shai-hulud-2]
+type nativeBlocklist struct {
+	SchemaVersion int           `json:"schema_version" yaml:"schema_version"`
+	Name          string        `json:"name,omitempty" yaml:"name,omitempty"`
+	Entries       []nativeEntry `json:"entries" yaml:"entries"`
+}
+
+// nativeEntry is one package in a native blocklist
+// Versions and Ranges may be combined; each version becomes its own entry.
+type nativeEntry struct {
+	Package     string        `json:"package" yaml:"package"`
+	Versions    []string      `json:"versions,omitempty" yaml:"versions,omitempty"`
+	Ranges      []nativeRange `json:"ranges,omitempty" yaml:"ranges,omitempty"`
+	Severity    Severity      `json:"severity,omitempty" yaml:"severity,omitempty"`
+	Reason      string        `json:"reason,omitempty" yaml:"reason,omitempty"`
+	Identifiers []string      `json:"identifiers,omitempty" yaml:"identifiers,omitempty"`
+	CVSSScore   float64       `json:"cvss_score,omitempty" yaml:"cvss_score,omitempty"`
+	CVSSVector  string        `json:"cvss_vector,omitempty" yaml:"cvss_vector,omitempty"`
+	FixedIn     []string      `json:"fixed_in,omitempty" yaml:"fixed_in,omitempty"`
+	References  []string      `json:"references,omitempty" yaml:"references,omitempty"`
+	Published   string        `json:"published,omitempty" yaml:"published,omitempty"`
+	Hashes      []string      `json:"hashes,omitempty" yaml:"hashes,omitempty"`
+	Tags        []string      `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// nativeRange is a VersionRange in the native schema
+type nativeRange struct {
+	Introduced   string `json:"introduced,omitempty" yaml:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty" yaml:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty" yaml:"last_affected,omitempty"`
+}
+
+// isNativeJSON reports whether a JSON document is a native blocklist
+// Native documents are objects with an "entries" key. OSV records also carry a
+// "schema_version", so that key alone does not identify the format.
+func isNativeJSON(data []byte) bool {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(bytes.TrimSpace(data), &doc); err != nil {
+		return false
+	}
+	_, ok := doc["entries"]
+	return ok
+}
+
+// isNativeYAML reports whether data is a YAML native blocklist
+// CSV content never decodes into a mapping, so this is safe to try before CSV.
+func isNativeYAML(data []byte) bool {
+	var doc map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return false
+	}
+	_, ok := doc["entries"]
+	return ok
+}
+
+// parseNativeJSON parses a native blocklist in JSON
+func parseNativeJSON(data []byte) ([]BlocklistEntry, error) {
+	var doc nativeBlocklist
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse blocklist JSON: %w", err)
+	}
+	return nativeEntries(doc)
+}
+
+// parseNativeYAML parses a native blocklist in YAML
+func parseNativeYAML(data []byte) ([]BlocklistEntry, error) {
+	var doc nativeBlocklist
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse blocklist YAML: %w", err)
+	}
+	return nativeEntries(doc)
+}
+
+// nativeEntries validates a native document and converts it into blocklist entries
+func nativeEntries(doc nativeBlocklist) ([]BlocklistEntry, error) {
+	if doc.SchemaVersion < 1 || doc.SchemaVersion > NativeSchemaVersion {
+		return nil, fmt.Errorf("unsupported blocklist schema_version %d (supported: 1-%d)",
+			doc.SchemaVersion, NativeSchemaVersion)
+	}
+
+	entries := make([]BlocklistEntry, 0, len(doc.Entries))
+	for i, item := range doc.Entries {
+		if item.Package == "" {
+			return nil, fmt.Errorf("blocklist entry %d: missing package", i+1)
+		}
+		if len(item.Versions) == 0 && len(item.Ranges) == 0 {
+			return nil, fmt.Errorf("blocklist entry %d (%s): needs versions or ranges", i+1, item.Package)
+		}
+
+		severity := Severity(strings.ToLower(string(item.Severity)))
+		if severity != "" && severityRank(severity) == 0 {
+			return nil, fmt.Errorf("blocklist entry %d (%s): unknown severity %q (want critical, high, medium, low or info)",
+				i+1, item.Package, item.Severity)
+		}
+
+		var published time.Time
+		if item.Published != "" {
+			var err error
+			if published, err = parsePublished(item.Published); err != nil {
+				return nil, fmt.Errorf("blocklist entry %d (%s): %w", i+1, item.Package, err)
+			}
+		}
+
+		base := BlocklistEntry{
+			PackageName: item.Package,
+			Severity:    severity,
+			Reason:      item.Reason,
+			Identifiers: item.Identifiers,
+			CVSSScore:   item.CVSSScore,
+			CVSSVector:  item.CVSSVector,
+			FixedIn:     item.FixedIn,
+			References:  item.References,
+			Published:   published,
+			Hashes:      item.Hashes,
+			Tags:        item.Tags,
+		}
+
+		for _, version := range item.Versions {
+			entry := base
+			entry.Version = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(version), "="))
+			entries = append(entries, entry)
+		}
+
+		if len(item.Ranges) > 0 {
+			entry := base
+			for _, r := range item.Ranges {
+				entry.Ranges = append(entry.Ranges, VersionRange(r))
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// parsePublished accepts a date ("2025-11-24") or an RFC 3339 timestamp
+func parsePublished(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid published date %q (want YYYY-MM-DD or RFC 3339)", value)
+	}
+	return t, nil
+}
+
+// hasYAMLExtension reports whether a path or URL names a YAML file
+func hasYAMLExtension(name string) bool {
+	// A git source names its file in the fragment
+	if isGitLocation(name) {
+		if _, file, _, err := parseGitLocation(name); err == nil {
+			name = file
+		}
+	}
+
+	// Strip any query string so URLs like list.yaml?token=x still match
+	if i := strings.IndexAny(name, "?#"); i >= 0 {
+		name = name[:i]
+	}
+	ext := strings.ToLower(path.Ext(name))
+	return ext == ".yaml" || ext == ".yml"
+}
@@ -0,0 +1,117 @@
+package scanner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadBlocklist_NativeYAML(t *testing.T) {
+	// Act
+	blocklist, err := LoadBlocklist("../../testdata/blocklists/shai-hulud-2.yaml")
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, blocklist.Entries, 3, "two tinycolor versions and one lodash range")
+
+	tinycolor := blocklist.IsBlocked("@ctrl/tinycolor", "4.1.2")
+	require.NotNil(t, tinycolor)
+	assert.Equal(t, SeverityCritical, tinycolor.Severity)
+	assert.Equal(t, []string{"4.1.3"}, tinycolor.FixedIn)
+	assert.Equal(t, []string{"shai-hulud-2"}, tinycolor.Tags)
+	assert.Len(t, tinycolor.References, 1)
+	assert.Len(t, tinycolor.Hashes, 1)
+	assert.Equal(t, time.Date(2025, 9, 15, 0, 0, 0, 0, time.UTC), tinycolor.Published)
+
+	lodash := blocklist.IsBlocked("lodash", "4.17.19")
+	require.NotNil(t, lodash)
+	assert.Equal(t, "CVE-2020-8203", lodash.CVE())
+	assert.Equal(t, 7.4, lodash.CVSSScore)
+	assert.Nil(t, blocklist.IsBlocked("lodash", "4.17.20"))
+}
+
+func TestLoadBlocklist_NativeJSON(t *testing.T) {
+	// Act
+	blocklist, err := LoadBlocklist("../../testdata/blocklists/shai-hulud-2.json")
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, blocklist.Entries, 1)
+
+	entry := blocklist.Entries[0]
+	assert.Equal(t, "02-echo", entry.PackageName)
+	assert.Equal(t, "0.0.7", entry.Version)
+	assert.Equal(t, SeverityCritical, entry.Severity, "severity defaults to critical")
+	assert.Equal(t, []string{"shai-hulud-2"}, entry.Tags)
+}
+
+func TestParseBlocklistData_NativeYAMLSniffed(t *testing.T) {
+	// Arrange - YAML content without a .yaml name (e.g. a cached download)
+	data := []byte("schema_version: 1\nentries:\n  - package: evil\n    versions: [1.0.0]\n")
+
+	// Act
+	blocklist, err := parseBlocklistData("blocklist-0123.csv", data)
+
+	// Assert
+	require.NoError(t, err)
+	assert.NotNil(t, blocklist.IsBlocked("evil", "1.0.0"))
+}
+
+func TestNativeEntries_SeverityCase(t *testing.T) {
+	// Arrange - severities are matched case-insensitively, as in CSV blocklists
+	data := []byte(`{"schema_version": 1, "entries": [{"package": "evil", "versions": ["1.0.0"], "severity": "CRITICAL"}]}`)
+
+	// Act
+	blocklist, err := parseBlocklistData("list.json", data)
+
+	// Assert
+	require.NoError(t, err)
+	entry := blocklist.IsBlocked("evil", "1.0.0")
+	require.NotNil(t, entry)
+	assert.Equal(t, SeverityCritical, entry.Severity)
+}
+
+func TestNativeEntries_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{"unknown schema version", "schema_version: 2\nentries: []\n", "unsupported blocklist schema_version 2"},
+		{"missing schema version", "entries: []\n", "unsupported blocklist schema_version 0"},
+		{"missing package", "schema_version: 1\nentries:\n  - versions: [1.0.0]\n", "entry 1: missing package"},
+		{"no versions", "schema_version: 1\nentries:\n  - package: evil\n", "entry 1 (evil): needs versions or ranges"},
+		{"unknown severity", "schema_version: 1\nentries:\n  - package: evil\n    versions: [1.0.0]\n    severity: urgent\n", "entry 1 (evil): unknown severity \"urgent\""},
+		{"bad date", "schema_version: 1\nentries:\n  - package: evil\n    versions: [1.0.0]\n    published: yesterday\n", "invalid published date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseBlocklistData("list.yaml", []byte(tt.data))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestMergeBlocklists_RichMetadata(t *testing.T) {
+	// Arrange
+	early := time.Date(2025, 9, 15, 0, 0, 0, 0, time.UTC)
+	a := newBlocklist([]BlocklistEntry{{PackageName: "evil", Version: "1.0.0", Tags: []string{"shai-hulud-2"},
+		References: []string{"https://a"}, Published: early.Add(48 * time.Hour)}})
+	b := newBlocklist([]BlocklistEntry{{PackageName: "evil", Version: "1.0.0", Tags: []string{"worm"},
+		References: []string{"https://a", "https://b"}, Hashes: []string{"sha256:00"}, Published: early}})
+
+	// Act
+	merged := MergeBlocklists(a, b)
+
+	// Assert
+	require.Len(t, merged.Entries, 1)
+	entry := merged.Entries[0]
+	assert.Equal(t, []string{"shai-hulud-2", "worm"}, entry.Tags)
+	assert.Equal(t, []string{"https://a", "https://b"}, entry.References)
+	assert.Equal(t, []string{"sha256:00"}, entry.Hashes)
+	assert.Equal(t, early, entry.Published)
+}
@@ -0,0 +1,260 @@
+package scanner
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ociManifestTypes are the manifest media types accepted from a registry
+var ociManifestTypes = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// ociTitleAnnotation names a layer's file (set by `oras push`)
+const ociTitleAnnotation = "org.opencontainers.image.title"
+
+// ociFetcher pulls a blocklist stored as an OCI artifact
+// Locations look like "oci://registry/repository:tag" (or "@sha256:..."). The
+// artifact holds the blocklist as its only layer, optionally next to a layer
+// whose title ends in ".minisig" or ".sig" with its detached signature. The
+// manifest digest serves as the cache validator.
+type ociFetcher struct {
+	http  *httpFetcher
+	token string // Registry token from the last auth challenge
+}
+
+// ociManifest is the part of an image manifest the fetcher needs
+type ociManifest struct {
+	MediaType string `json:"mediaType"`
+	Layers    []struct {
+		MediaType   string            `json:"mediaType"`
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"layers"`
+}
+
+// isOCILocation reports whether a location is an OCI artifact
+func isOCILocation(location string) bool {
+	return strings.HasPrefix(location, "oci://")
+}
+
+// parseOCILocation splits "oci://registry/repository[:tag|@digest]"
+func parseOCILocation(location string) (registry, repository, reference string, err error) {
+	rest := strings.TrimPrefix(location, "oci://")
+	registry, repository, ok := strings.Cut(rest, "/")
+	if !ok || registry == "" || repository == "" {
+		return "", "", "", fmt.Errorf("OCI source %s needs a registry and a repository: oci://registry/repository:tag", location)
+	}
+
+	reference = "latest"
+	if i := strings.Index(repository, "@"); i >= 0 {
+		repository, reference = repository[:i], repository[i+1:]
+	} else if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository, reference = repository[:i], repository[i+1:]
+	}
+	if repository == "" || reference == "" {
+		return "", "", "", fmt.Errorf("invalid OCI source %s", location)
+	}
+	return registry, repository, reference, nil
+}
+
+// fetchIfChanged pulls the manifest and, unless its digest is the cached one, the blocklist layer
+func (f *ociFetcher) fetchIfChanged(location string, auth *Auth, previous cacheMeta) (*fetchResult, error) {
+	registry, repository, reference, err := parseOCILocation(location)
+	if err != nil {
+		return nil, err
+	}
+	base := fmt.Sprintf("https://%s/v2/%s", registry, repository)
+
+	fmt.Fprintf(os.Stderr, "   Pulling OCI artifact: %s/%s:%s\n", registry, repository, reference)
+
+	resp, body, err := f.get(base+"/manifests/"+reference, strings.Join(ociManifestTypes, ", "), auth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull manifest: %w", err)
+	}
+
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		digest = fmt.Sprintf("sha256:%x", sha256.Sum256(body))
+	}
+	if previous.ETag != "" && digest == previous.ETag {
+		return &fetchResult{ETag: digest, NotModified: true}, nil
+	}
+
+	var manifest ociManifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid OCI manifest: %w", err)
+	}
+
+	var dataLayer, sigLayer string
+	layers := 0
+	for _, layer := range manifest.Layers {
+		title := layer.Annotations[ociTitleAnnotation]
+		if strings.HasSuffix(title, ".minisig") || strings.HasSuffix(title, ".sig") {
+			sigLayer = layer.Digest
+			continue
+		}
+		dataLayer = layer.Digest
+		layers++
+	}
+	if layers != 1 {
+		return nil, fmt.Errorf("OCI artifact %s has %d blocklist layers; expected exactly one", location, layers)
+	}
+
+	result := &fetchResult{ETag: digest}
+	if result.Data, err = f.blob(base, dataLayer, auth); err != nil {
+		return nil, err
+	}
+	if sigLayer != "" {
+		if result.Signature, err = f.blob(base, sigLayer, auth); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// fetchSignature returns nil: signature layers come with the artifact in fetchIfChanged
+func (f *ociFetcher) fetchSignature(string, *Auth) []byte {
+	return nil
+}
+
+// blob downloads a blob and checks it against its digest
+func (f *ociFetcher) blob(base, digest string, auth *Auth) ([]byte, error) {
+	algorithm, expected, _ := strings.Cut(digest, ":")
+	if algorithm != "sha256" {
+		return nil, fmt.Errorf("unsupported layer digest %q", digest)
+	}
+
+	_, body, err := f.get(base+"/blobs/"+digest, "", auth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull layer %s: %w", digest, err)
+	}
+	if actual := fmt.Sprintf("%x", sha256.Sum256(body)); actual != expected {
+		return nil, fmt.Errorf("layer %s does not match its digest (got sha256:%s)", digest, actual)
+	}
+	return body, nil
+}
+
+// get requests a registry URL, answering a Bearer auth challenge once
+func (f *ociFetcher) get(target, accept string, auth *Auth) (*http.Response, []byte, error) {
+	headers := http.Header{}
+	if accept != "" {
+		headers.Set("Accept", accept)
+	}
+
+	authorization := ""
+	if f.token != "" {
+		authorization = "Bearer " + f.token
+	} else if auth != nil && (auth.Token != "" || auth.TokenEnv != "") {
+		var err error
+		if authorization, err = auth.header(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if authorization != "" {
+		headers.Set("Authorization", authorization)
+	}
+
+	resp, body, err := f.http.get(target, headers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized && authorization == "" {
+		if authorization, err = f.authenticate(resp.Header.Get("WWW-Authenticate"), auth); err != nil {
+			return nil, nil, err
+		}
+		headers.Set("Authorization", authorization)
+		if resp, body, err = f.http.get(target, headers); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, nil, fmt.Errorf("HTTP %d (check the source's auth settings)", resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return nil, nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return resp, body, nil
+}
+
+// challengeParam matches key="value" pairs of a WWW-Authenticate header
+var challengeParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// authenticate answers a registry's WWW-Authenticate challenge
+// Basic challenges use the source's username and password; Bearer challenges
+// exchange them (or nothing, for anonymous pulls) for a token at the realm.
+func (f *ociFetcher) authenticate(challenge string, auth *Auth) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+
+	basic, err := auth.header()
+	if err != nil {
+		return "", err
+	}
+
+	if strings.EqualFold(scheme, "Basic") {
+		if basic == "" {
+			return "", fmt.Errorf("registry requires credentials; set auth.username and auth.password for this source")
+		}
+		return basic, nil
+	}
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("unsupported registry auth challenge %q", challenge)
+	}
+
+	values := make(map[string]string)
+	for _, match := range challengeParam.FindAllStringSubmatch(params, -1) {
+		values[match[1]] = match[2]
+	}
+	if values["realm"] == "" {
+		return "", fmt.Errorf("registry auth challenge has no realm")
+	}
+
+	query := url.Values{}
+	for _, key := range []string{"service", "scope"} {
+		if values[key] != "" {
+			query.Set(key, values[key])
+		}
+	}
+	tokenURL := values["realm"]
+	if len(query) > 0 {
+		tokenURL += "?" + query.Encode()
+	}
+
+	headers := http.Header{}
+	if basic != "" {
+		headers.Set("Authorization", basic)
+	}
+	resp, body, err := f.http.get(tokenURL, headers)
+	if err != nil {
+		return "", fmt.Errorf("failed to get registry token: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get registry token: HTTP %d", resp.StatusCode)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", fmt.Errorf("invalid registry token response: %w", err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	if token.Token == "" {
+		return "", fmt.Errorf("registry token response has no token")
+	}
+
+	f.token = token.Token
+	return "Bearer " + token.Token, nil
+}
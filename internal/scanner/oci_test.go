@@ -0,0 +1,199 @@
+package scanner
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/signature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRegistry serves one artifact at /v2/iocs/npm behind a token challenge
+type testRegistry struct {
+	server    *httptest.Server
+	blobs     map[string][]byte
+	manifest  []byte
+	blobPulls int
+}
+
+// newTestRegistry starts a TLS registry holding the given layers (title -> content)
+func newTestRegistry(t *testing.T, layers map[string][]byte) *testRegistry {
+	t.Helper()
+	registry := &testRegistry{blobs: make(map[string][]byte)}
+	registry.server = httptest.NewTLSServer(http.HandlerFunc(registry.serve))
+	t.Cleanup(registry.server.Close)
+	registry.push(t, layers)
+	return registry
+}
+
+// push replaces the artifact's manifest
+func (r *testRegistry) push(t *testing.T, layers map[string][]byte) {
+	t.Helper()
+	type layer struct {
+		MediaType   string            `json:"mediaType"`
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+	}
+	manifest := struct {
+		MediaType string  `json:"mediaType"`
+		Layers    []layer `json:"layers"`
+	}{MediaType: ociManifestTypes[0]}
+
+	for title, content := range layers {
+		digest := fmt.Sprintf("sha256:%x", sha256.Sum256(content))
+		r.blobs[digest] = content
+		manifest.Layers = append(manifest.Layers, layer{
+			MediaType:   "application/octet-stream",
+			Digest:      digest,
+			Annotations: map[string]string{ociTitleAnnotation: title},
+		})
+	}
+
+	var err error
+	r.manifest, err = json.Marshal(manifest)
+	require.NoError(t, err)
+}
+
+// location returns the oci:// location of the artifact
+func (r *testRegistry) location() string {
+	return "oci://" + strings.TrimPrefix(r.server.URL, "https://") + "/iocs/npm:v1"
+}
+
+func (r *testRegistry) serve(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/token" {
+		if req.URL.Query().Get("scope") != "repository:iocs/npm:pull" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"token":"pull-token"}`))
+		return
+	}
+	if req.Header.Get("Authorization") != "Bearer pull-token" {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+			`Bearer realm="%s/token",service="test",scope="repository:iocs/npm:pull"`, r.server.URL))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case req.URL.Path == "/v2/iocs/npm/manifests/v1":
+		w.Header().Set("Content-Type", ociManifestTypes[0])
+		_, _ = w.Write(r.manifest)
+	case strings.HasPrefix(req.URL.Path, "/v2/iocs/npm/blobs/"):
+		blob, ok := r.blobs[strings.TrimPrefix(req.URL.Path, "/v2/iocs/npm/blobs/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		r.blobPulls++
+		_, _ = w.Write(blob)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestParseOCILocation(t *testing.T) {
+	tests := []struct {
+		location   string
+		registry   string
+		repository string
+		reference  string
+	}{
+		{"oci://ghcr.io/org/iocs:v2", "ghcr.io", "org/iocs", "v2"},
+		{"oci://localhost:5000/iocs", "localhost:5000", "iocs", "latest"},
+		{"oci://ghcr.io/org/iocs@sha256:abc", "ghcr.io", "org/iocs", "sha256:abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.location, func(t *testing.T) {
+			registry, repository, reference, err := parseOCILocation(tt.location)
+			require.NoError(t, err)
+			assert.Equal(t, tt.registry, registry)
+			assert.Equal(t, tt.repository, repository)
+			assert.Equal(t, tt.reference, reference)
+		})
+	}
+
+	_, _, _, err := parseOCILocation("oci://ghcr.io")
+	assert.Error(t, err)
+}
+
+func TestLoader_OCISource(t *testing.T) {
+	// Arrange
+	registry := newTestRegistry(t, map[string][]byte{"npm.csv": []byte(testCSV)})
+	caPath := writePEM(t, "ca.pem", "CERTIFICATE", registry.server.Certificate().Raw)
+	cacheDir := t.TempDir()
+	loader := &Loader{CacheDir: cacheDir, HTTP: HTTPOptions{CACert: caPath}}
+	expire := func() {
+		old := time.Now().Add(-2 * time.Hour)
+		require.NoError(t, os.Chtimes(getCachePath(registry.location(), cacheDir), old, old))
+	}
+
+	// Act - pull, then re-check an unchanged artifact, then a new push
+	first, err := loader.Load(registry.location())
+	require.NoError(t, err)
+	pullsAfterFirst := registry.blobPulls
+
+	expire()
+	_, err = loader.Load(registry.location())
+	require.NoError(t, err)
+	pullsAfterUnchanged := registry.blobPulls
+
+	registry.push(t, map[string][]byte{"npm.csv": []byte(testCSV + "worse,= 2.0.0\n")})
+	expire()
+	updated, err := loader.Load(registry.location())
+	require.NoError(t, err)
+
+	// Assert
+	assert.Len(t, first.Entries, 1)
+	assert.Equal(t, 1, pullsAfterFirst)
+	assert.Equal(t, 1, pullsAfterUnchanged, "an unchanged manifest digest skips the layer")
+	assert.Len(t, updated.Entries, 2)
+}
+
+func TestLoader_OCISourceErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		layers      map[string][]byte
+		expectedErr string
+	}{
+		{"no layers", map[string][]byte{}, "has 0 blocklist layers"},
+		{"two layers", map[string][]byte{"a.csv": []byte(testCSV), "b.csv": []byte(testCSV)}, "has 2 blocklist layers"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := newTestRegistry(t, tt.layers)
+			caPath := writePEM(t, "ca.pem", "CERTIFICATE", registry.server.Certificate().Raw)
+			loader := &Loader{HTTP: HTTPOptions{CACert: caPath}}
+
+			_, err := loader.Load(registry.location())
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
+
+func TestLoader_OCISourceSignatureLayer(t *testing.T) {
+	priv, key := testSigner(t)
+	registry := newTestRegistry(t, map[string][]byte{
+		"npm.csv":     signedCSV,
+		"npm.csv.sig": sign(priv, signedCSV),
+	})
+	caPath := writePEM(t, "ca.pem", "CERTIFICATE", registry.server.Certificate().Raw)
+	loader := &Loader{HTTP: HTTPOptions{CACert: caPath}, TrustedKeys: []signature.PublicKey{key}, RequireSigned: true}
+
+	blocklist, err := loader.Load(registry.location())
+
+	require.NoError(t, err)
+	assert.NotNil(t, blocklist.IsBlocked("evil", "1.0.0"))
+}
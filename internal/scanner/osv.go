@@ -0,0 +1,335 @@
+package scanner
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// osvRecord is the subset of the OSV schema (https://ossf.github.io/osv-schema/) we use
+type osvRecord struct {
+	ID         string     `json:"id"`
+	Summary    string     `json:"summary"`
+	Details    string     `json:"details"`
+	Aliases    []string   `json:"aliases"`
+	Published  time.Time  `json:"published"`
+	Withdrawn  *time.Time `json:"withdrawn"`
+	References []struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	} `json:"references"`
+	Affected []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		Ranges []struct {
+			Type   string              `json:"type"`
+			Events []map[string]string `json:"events"`
+		} `json:"ranges"`
+		Versions []string `json:"versions"`
+	} `json:"affected"`
+	Severity []struct {
+		Type  string `json:"type"`
+		Score string `json:"score"`
+	} `json:"severity"`
+	DatabaseSpecific struct {
+		Severity string   `json:"severity"`
+		Tags     []string `json:"tags"` // Written by hulud-scan's own OSV export
+	} `json:"database_specific"`
+}
+
+// parseAdvisoryJSON parses advisory JSON into blocklist entries
+// Both OSV records (including the GitHub Advisory Database repository, which
+// uses OSV) and GitHub REST API advisory exports are accepted.
+func parseAdvisoryJSON(data []byte) ([]BlocklistEntry, error) {
+	if isGHSAExport(data) {
+		advisories, err := decodeGHSAAdvisories(data)
+		if err != nil {
+			return nil, err
+		}
+		return ghsaEntries(advisories), nil
+	}
+
+	records, err := decodeOSVRecords(data)
+	if err != nil {
+		return nil, err
+	}
+	return osvEntries(records), nil
+}
+
+// decodeOSVRecords decodes either one record or a JSON array of records
+// A record without affected packages decodes fine and yields no entries, so one
+// such file does not abort loading a whole export.
+func decodeOSVRecords(data []byte) ([]osvRecord, error) {
+	trimmed := bytes.TrimSpace(data)
+
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var records []osvRecord
+		if err := json.Unmarshal(trimmed, &records); err != nil {
+			return nil, fmt.Errorf("failed to parse OSV records: %w", err)
+		}
+		return records, nil
+	}
+
+	var record osvRecord
+	if err := json.Unmarshal(trimmed, &record); err != nil {
+		return nil, fmt.Errorf("failed to parse OSV record: %w", err)
+	}
+	if record.ID == "" {
+		return nil, fmt.Errorf("failed to parse OSV record: missing id")
+	}
+	return []osvRecord{record}, nil
+}
+
+// loadAdvisoryDir loads every *.json advisory below a directory
+func loadAdvisoryDir(dir string) (*Blocklist, error) {
+	entries := make([]BlocklistEntry, 0)
+	files := 0
+
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".json") {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		parsed, err := parseAdvisoryJSON(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		entries = append(entries, parsed...)
+		files++
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load advisory directory: %w", err)
+	}
+
+	if files == 0 {
+		return nil, fmt.Errorf("no advisory records found in %s", dir)
+	}
+
+	return newBlocklist(entries), nil
+}
+
+// parseAdvisoryZip loads every *.json advisory in a zip export (e.g. the OSV npm all.zip dump)
+func parseAdvisoryZip(data []byte) (*Blocklist, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open advisory zip: %w", err)
+	}
+
+	entries := make([]BlocklistEntry, 0, len(archive.File))
+	files := 0
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !strings.EqualFold(filepath.Ext(file.Name), ".json") {
+			continue
+		}
+
+		content, err := readZipFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from advisory zip: %w", file.Name, err)
+		}
+
+		parsed, err := parseAdvisoryJSON(content)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Name, err)
+		}
+		entries = append(entries, parsed...)
+		files++
+	}
+
+	if files == 0 {
+		return nil, fmt.Errorf("no advisory records found in zip")
+	}
+
+	return newBlocklist(entries), nil
+}
+
+// readZipFile reads one file from a zip archive
+func readZipFile(file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rc.Close()
+	}()
+	return io.ReadAll(rc)
+}
+
+// osvEntries converts OSV records into blocklist entries
+// Only the npm ecosystem is relevant. SEMVER/ECOSYSTEM ranges become one
+// range entry; without usable ranges, each explicit version becomes an entry.
+func osvEntries(records []osvRecord) []BlocklistEntry {
+	entries := make([]BlocklistEntry, 0, len(records))
+
+	for _, record := range records {
+		// A withdrawn record was published in error and no longer applies
+		if record.Withdrawn != nil {
+			continue
+		}
+
+		severity := osvSeverity(record)
+		reason := osvReason(record)
+		identifiers := appendUnique([]string{record.ID}, record.Aliases...)
+		vector := osvCVSSVector(record)
+
+		var references []string
+		for _, ref := range record.References {
+			references = appendUnique(references, ref.URL)
+		}
+
+		for _, affected := range record.Affected {
+			if !strings.EqualFold(affected.Package.Ecosystem, "npm") || affected.Package.Name == "" {
+				continue
+			}
+
+			base := BlocklistEntry{
+				PackageName: affected.Package.Name,
+				Severity:    severity,
+				Reason:      reason,
+				Identifiers: identifiers,
+				CVSSVector:  vector,
+				References:  references,
+				Published:   record.Published,
+				Tags:        record.DatabaseSpecific.Tags,
+			}
+
+			ranges := make([]VersionRange, 0)
+			for _, r := range affected.Ranges {
+				if r.Type == "SEMVER" || r.Type == "ECOSYSTEM" {
+					ranges = append(ranges, osvRanges(r.Events)...)
+				}
+			}
+
+			if len(ranges) > 0 {
+				entry := base
+				entry.Ranges = ranges
+				entry.FixedIn = fixedVersions(ranges)
+				entries = append(entries, entry)
+				continue
+			}
+
+			for _, version := range affected.Versions {
+				entry := base
+				entry.Version = version
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	return entries
+}
+
+// osvRanges turns an ordered OSV event list into version ranges
+// Events come as introduced, then fixed/last_affected/limit, possibly repeated.
+func osvRanges(events []map[string]string) []VersionRange {
+	ranges := make([]VersionRange, 0, 1)
+	var current *VersionRange
+
+	for _, event := range events {
+		if introduced, ok := event["introduced"]; ok {
+			if current != nil {
+				ranges = append(ranges, *current)
+			}
+			current = &VersionRange{Introduced: introduced}
+			continue
+		}
+
+		if current == nil {
+			continue // Malformed: an upper bound without a start
+		}
+
+		if fixed, ok := event["fixed"]; ok {
+			current.Fixed = fixed
+		} else if last, ok := event["last_affected"]; ok {
+			current.LastAffected = last
+		} else if limit, ok := event["limit"]; ok && limit != "*" {
+			current.Fixed = limit
+		}
+
+		ranges = append(ranges, *current)
+		current = nil
+	}
+
+	if current != nil {
+		ranges = append(ranges, *current)
+	}
+	return ranges
+}
+
+// osvSeverity maps database_specific.severity (GHSA style) to our levels
+// Malicious package records (MAL-) are always critical.
+func osvSeverity(record osvRecord) Severity {
+	if strings.HasPrefix(record.ID, "MAL-") {
+		return SeverityCritical
+	}
+	return advisorySeverity(record.DatabaseSpecific.Severity)
+}
+
+// advisorySeverity maps GitHub advisory severities (CRITICAL, HIGH, MODERATE, LOW)
+// Unknown or missing severities default to medium.
+func advisorySeverity(severity string) Severity {
+	switch strings.ToUpper(severity) {
+	case "CRITICAL":
+		return SeverityCritical
+	case "HIGH":
+		return SeverityHigh
+	case "MODERATE", "MEDIUM":
+		return SeverityMedium
+	case "LOW":
+		return SeverityLow
+	default:
+		return SeverityMedium
+	}
+}
+
+// osvReason builds a one-line reason that keeps the record id visible
+func osvReason(record osvRecord) string {
+	summary := strings.TrimSpace(record.Summary)
+	if summary == "" {
+		summary = strings.TrimSpace(strings.SplitN(record.Details, "\n", 2)[0])
+	}
+	if summary == "" {
+		summary = "Listed in OSV"
+	}
+	return fmt.Sprintf("%s (%s)", summary, record.ID)
+}
+
+// osvCVSSVector returns the CVSS vector published in the record's severity list
+func osvCVSSVector(record osvRecord) string {
+	for _, severity := range record.Severity {
+		if strings.HasPrefix(severity.Type, "CVSS_") {
+			return severity.Score
+		}
+	}
+	return ""
+}
+
+// fixedVersions collects the fixed versions of a set of ranges
+func fixedVersions(ranges []VersionRange) []string {
+	fixed := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		if r.Fixed != "" {
+			fixed = appendUnique(fixed, r.Fixed)
+		}
+	}
+	if len(fixed) == 0 {
+		return nil
+	}
+	return fixed
+}
@@ -0,0 +1,176 @@
+package scanner
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadBlocklist_OSVFile(t *testing.T) {
+	// Arrange - a malicious-package record with an explicit version
+	path := "../../testdata/osv/MAL-2025-47124.json"
+
+	// Act
+	blocklist, err := LoadBlocklist(path)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, blocklist.Entries, 1)
+
+	entry := blocklist.Entries[0]
+	assert.Equal(t, "02-echo", entry.PackageName)
+	assert.Equal(t, "0.0.7", entry.Version)
+	assert.Equal(t, SeverityCritical, entry.Severity, "MAL- records are always critical")
+	assert.Contains(t, entry.Reason, "MAL-2025-47124")
+}
+
+func TestLoadBlocklist_OSVRanges(t *testing.T) {
+	blocklist, err := LoadBlocklist("../../testdata/osv/GHSA-p6mc-m468-83gw.json")
+	require.NoError(t, err)
+
+	// The PyPI package of the same name is ignored
+	require.Len(t, blocklist.Entries, 1)
+	entry := blocklist.Entries[0]
+	assert.Equal(t, SeverityHigh, entry.Severity)
+	assert.Equal(t, []string{"GHSA-p6mc-m468-83gw", "CVE-2020-8203"}, entry.Identifiers)
+	assert.Equal(t, "CVE-2020-8203", entry.CVE())
+	assert.Equal(t, "CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:N/I:H/A:H", entry.CVSSVector)
+	assert.Equal(t, []VersionRange{{Introduced: "3.7.0", Fixed: "4.17.20"}}, entry.Ranges)
+	assert.Equal(t, []string{"4.17.20"}, entry.FixedIn)
+
+	tests := []struct {
+		version string
+		blocked bool
+	}{
+		{"3.6.9", false},
+		{"3.7.0", true},
+		{"4.17.19", true},
+		{"4.17.20", false},
+		{"4.17.21", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.blocked, blocklist.IsBlocked("lodash", tt.version) != nil)
+		})
+	}
+}
+
+func TestLoadBlocklist_OSVDirectory(t *testing.T) {
+	blocklist, err := LoadBlocklist("../../testdata/osv")
+
+	require.NoError(t, err)
+	assert.Len(t, blocklist.Entries, 3)
+	assert.NotNil(t, blocklist.IsBlocked("02-echo", "0.0.7"))
+	assert.NotNil(t, blocklist.IsBlocked("@ctrl/tinycolor", "4.1.2"), "last_affected is inclusive")
+	assert.Nil(t, blocklist.IsBlocked("@ctrl/tinycolor", "4.1.3"))
+}
+
+func TestLoadBlocklist_OSVZip(t *testing.T) {
+	// Arrange - bundle the fixtures like the osv.dev npm/all.zip export
+	zipPath := filepath.Join(t.TempDir(), "all.zip")
+	file, err := os.Create(zipPath)
+	require.NoError(t, err)
+
+	archive := zip.NewWriter(file)
+	for _, name := range []string{"MAL-2025-47124.json", "GHSA-p6mc-m468-83gw.json"} {
+		data, err := os.ReadFile(filepath.Join("../../testdata/osv", name))
+		require.NoError(t, err)
+
+		w, err := archive.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, archive.Close())
+	require.NoError(t, file.Close())
+
+	// Act
+	blocklist, err := LoadBlocklist(zipPath)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Len(t, blocklist.Entries, 2)
+	assert.NotNil(t, blocklist.IsBlocked("02-echo", "0.0.7"))
+	assert.NotNil(t, blocklist.IsBlocked("lodash", "4.17.15"))
+}
+
+func TestLoadBlocklist_OSVSkippedRecords(t *testing.T) {
+	// Arrange - a withdrawn record and one without npm packages next to a usable one
+	dir := t.TempDir()
+	records := map[string]string{
+		"GHSA-withdrawn.json": `{"id": "GHSA-withdrawn", "withdrawn": "2024-01-02T00:00:00Z",
+			"affected": [{"package": {"ecosystem": "npm", "name": "withdrawn"}, "versions": ["1.0.0"]}]}`,
+		"GHSA-empty.json": `{"id": "GHSA-empty", "affected": []}`,
+		"GHSA-pypi.json": `{"id": "GHSA-pypi",
+			"affected": [{"package": {"ecosystem": "PyPI", "name": "evil"}, "versions": ["1.0.0"]}]}`,
+		"MAL-1.json": `{"id": "MAL-1",
+			"affected": [{"package": {"ecosystem": "npm", "name": "evil"}, "versions": ["1.0.0"]}]}`,
+	}
+	for name, data := range records {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600))
+	}
+
+	// Act
+	blocklist, err := LoadBlocklist(dir)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, blocklist.Entries, 1)
+	assert.Equal(t, "evil", blocklist.Entries[0].PackageName)
+	assert.Nil(t, blocklist.IsBlocked("withdrawn", "1.0.0"))
+}
+
+func TestOSVRanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		events   []map[string]string
+		expected []VersionRange
+	}{
+		{
+			name:     "every version",
+			events:   []map[string]string{{"introduced": "0"}},
+			expected: []VersionRange{{Introduced: "0"}},
+		},
+		{
+			name: "two windows",
+			events: []map[string]string{
+				{"introduced": "1.0.0"}, {"fixed": "1.0.5"},
+				{"introduced": "2.0.0"}, {"fixed": "2.0.1"},
+			},
+			expected: []VersionRange{
+				{Introduced: "1.0.0", Fixed: "1.0.5"},
+				{Introduced: "2.0.0", Fixed: "2.0.1"},
+			},
+		},
+		{
+			name:     "limit",
+			events:   []map[string]string{{"introduced": "1.0.0"}, {"limit": "3.0.0"}},
+			expected: []VersionRange{{Introduced: "1.0.0", Fixed: "3.0.0"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, osvRanges(tt.events))
+		})
+	}
+}
+
+func TestLoadOrDownloadBlocklist_CachesOriginalBytes(t *testing.T) {
+	// An OSV record must survive the cache round trip with its ranges intact
+	data, err := os.ReadFile("../../testdata/osv/GHSA-p6mc-m468-83gw.json")
+	require.NoError(t, err)
+
+	tmpDir := t.TempDir()
+	require.NoError(t, saveToCache("https://example.com/osv.json", tmpDir, data, nil, cacheMeta{}))
+
+	loader := &Loader{CacheDir: tmpDir, Offline: true}
+	cached, err := loader.Load("https://example.com/osv.json")
+	require.NoError(t, err)
+	require.Len(t, cached.Entries, 1)
+	assert.NotEmpty(t, cached.Entries[0].Ranges)
+}
@@ -0,0 +1,61 @@
+package scanner
+
+// Rule IDs, as set on Finding.RuleID
+const (
+	RuleCompromisedPackage   = "compromised-package"            // Blocklisted package version
+	RuleMissingDependency    = "lockfile-missing-dependency"    // Declared in package.json, absent from the lockfile
+	RuleUndeclaredDependency = "lockfile-undeclared-dependency" // In the lockfile's root entry, absent from package.json
+	RuleRangeMismatch        = "lockfile-range-mismatch"        // Resolved version outside the declared range
+)
+
+// Rule describes a check that produces findings
+type Rule struct {
+	ID          string   // Stable identifier (e.g. "compromised-package")
+	Name        string   // CamelCase name, as SARIF wants it
+	Title       string   // One-line summary
+	Description string   // What the check looks for and why it matters
+	Severity    Severity // Severity of its findings (blocklist findings carry their own)
+}
+
+// Rules lists every check, in the order reports should present them
+var Rules = []Rule{
+	{
+		ID:          RuleCompromisedPackage,
+		Name:        "CompromisedPackage",
+		Title:       "Known compromised package version",
+		Description: "A package version in the lockfile matches an entry in the hulud-scan blocklist.",
+		Severity:    SeverityCritical,
+	},
+	{
+		ID:          RuleMissingDependency,
+		Name:        "LockfileMissingDependency",
+		Title:       "Dependency missing from the lockfile",
+		Description: "package.json declares a dependency the lockfile does not install. The lockfile was edited by hand or is out of date.",
+		Severity:    SeverityHigh,
+	},
+	{
+		ID:          RuleUndeclaredDependency,
+		Name:        "LockfileUndeclaredDependency",
+		Title:       "Lockfile dependency not in package.json",
+		Description: "The lockfile's root entry lists a dependency package.json does not declare. Packages added to a lockfile by hand are installed without ever appearing in package.json.",
+		Severity:    SeverityHigh,
+	},
+	{
+		ID:          RuleRangeMismatch,
+		Name:        "LockfileRangeMismatch",
+		Title:       "Resolved version outside the declared range",
+		Description: "The lockfile resolves a direct dependency to a version package.json does not allow, or records a different range than package.json declares. A package manager would never write this, so the lockfile was edited or tampered with.",
+		Severity:    SeverityHigh,
+	},
+}
+
+// LookupRule returns the rule with the given ID
+// Unknown and empty IDs return the compromised package rule, which findings had before rules existed.
+func LookupRule(id string) Rule {
+	for _, rule := range Rules {
+		if rule.ID == id {
+			return rule
+		}
+	}
+	return Rules[0]
+}
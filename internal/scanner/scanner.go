@@ -1,131 +1,122 @@
 package scanner
 
 import (
+	"bytes"
 	"encoding/csv"
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/fullstack-spiderman/hulud-scan/internal/graph"
 )
 
-// LoadBlocklist loads a blocklist from a CSV file
+// LoadBlocklist loads a blocklist from a file or directory
+// The format is detected from the content (see parseBlocklistData); a
+// directory is read as a collection of OSV records.
 func LoadBlocklist(path string) (*Blocklist, error) {
-	// Open the CSV file
-	file, err := os.Open(path)
+	blocklist, err := loadBlocklistFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open blocklist file: %w", err)
+		return nil, err
 	}
-	defer func() {
-		if closeErr := file.Close(); closeErr != nil {
-			// Log the error but don't override the return error
-			fmt.Printf("Warning: failed to close file: %v\n", closeErr)
-		}
-	}()
-
-	// Create CSV reader
-	reader := csv.NewReader(file)
-	return parseBlocklistCSV(reader)
+	Source{Location: path}.applyDefaults(blocklist.Entries)
+	return blocklist, nil
 }
 
-// parseBlocklistCSV parses CSV into Blocklist
-// Supports two formats:
-// 1. Full format: package_name,version,severity,reason,cve
-// 2. Wiz format: Package,Version (with "=" prefix)
-func parseBlocklistCSV(reader *csv.Reader) (*Blocklist, error) {
-	// Read all records
-	records, err := reader.ReadAll()
+// loadBlocklistFile reads and parses a blocklist file or advisory directory
+func loadBlocklistFile(path string) (*Blocklist, error) {
+	info, err := os.Stat(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read CSV: %w", err)
+		return nil, fmt.Errorf("failed to open blocklist file: %w", err)
 	}
 
-	// Check if file is empty
-	if len(records) < 2 {
-		return nil, fmt.Errorf("blocklist file is empty or missing header")
+	if info.IsDir() {
+		return loadAdvisoryDir(path)
 	}
 
-	// Read header to detect format
-	header := records[0]
-	isWizFormat := len(header) == 2 &&
-		(strings.ToLower(header[0]) == "package" || strings.ToLower(header[0]) == "package_name")
-
-	// Skip header
-	records = records[1:]
-
-	// Parse entries
-	entries := make([]BlocklistEntry, 0, len(records))
-	index := make(map[string][]int)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blocklist file: %w", err)
+	}
 
-	for i, record := range records {
-		if len(record) < 2 {
-			continue // Skip malformed rows
-		}
+	return parseBlocklistData(path, data)
+}
 
-		var entry BlocklistEntry
+// Blocklist formats, as detected by DetectFormat
+const (
+	FormatCSV        = "csv"      // Full or Wiz CSV
+	FormatNativeJSON = "json"     // Native format, JSON encoding
+	FormatNativeYAML = "yaml"     // Native format, YAML encoding
+	FormatAdvisory   = "advisory" // OSV or GitHub advisory JSON
+	FormatZip        = "zip"      // Zip archive of advisory JSON
+)
 
-		if isWizFormat {
-			// Wiz format: Package,Version (e.g., "lodash", "= 0.0.7")
-			entry = parseWizEntry(record)
-		} else {
-			// Full format: package_name,version,severity,reason,cve
-			if len(record) < 4 {
-				continue
-			}
+// DetectFormat works out the format of blocklist data
+// name (a path or URL) is only used for its extension: ".yaml"/".yml" forces
+// the native YAML format. Otherwise the content decides: zip archives of
+// advisories, native JSON (has "entries"), advisory JSON (OSV or GitHub
+// advisories; one record or an array), native YAML, and CSV (full or Wiz format).
+func DetectFormat(name string, data []byte) string {
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return FormatZip
+	}
 
-			entry = BlocklistEntry{
-				PackageName: strings.TrimSpace(record[0]),
-				Version:     strings.TrimSpace(record[1]),
-				Severity:    Severity(strings.TrimSpace(record[2])),
-				Reason:      strings.TrimSpace(record[3]),
-			}
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case hasYAMLExtension(name):
+		return FormatNativeYAML
+	case len(trimmed) > 0 && trimmed[0] == '{' && isNativeJSON(trimmed):
+		return FormatNativeJSON
+	case len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '['):
+		return FormatAdvisory
+	case isNativeYAML(data):
+		return FormatNativeYAML
+	default:
+		return FormatCSV
+	}
+}
 
-			// CVE is optional (column 5)
-			if len(record) >= 5 {
-				entry.CVE = strings.TrimSpace(record[4])
-			}
-		}
+// parseBlocklistData detects the blocklist format (see DetectFormat) and parses it
+// Severity and reason are left empty where the data has none; see Source.applyDefaults.
+func parseBlocklistData(name string, data []byte) (*Blocklist, error) {
+	var entries []BlocklistEntry
+	var err error
+
+	switch DetectFormat(name, data) {
+	case FormatZip:
+		return parseAdvisoryZip(data)
+	case FormatNativeJSON:
+		entries, err = parseNativeJSON(data)
+	case FormatAdvisory:
+		entries, err = parseAdvisoryJSON(data)
+	case FormatNativeYAML:
+		entries, err = parseNativeYAML(data)
+	default:
+		return parseBlocklistCSV(csv.NewReader(bytes.NewReader(data)))
+	}
 
-		entries = append(entries, entry)
+	if err != nil {
+		return nil, err
+	}
+	return newBlocklist(entries), nil
+}
 
-		// Build index for fast lookup
+// newBlocklist wraps entries in a Blocklist and builds its lookup index
+// The index holds positions in entries, so it must be rebuilt whenever entries change.
+func newBlocklist(entries []BlocklistEntry) *Blocklist {
+	index := make(map[string][]int)
+	for i, entry := range entries {
 		index[entry.PackageName] = append(index[entry.PackageName], i)
 	}
 
 	return &Blocklist{
 		Entries: entries,
 		Index:   index,
-	}, nil
-}
-
-// parseWizEntry parses a Wiz format CSV entry
-// Format: "package-name", "= version" or "= v1 || = v2"
-func parseWizEntry(record []string) BlocklistEntry {
-	packageName := strings.TrimSpace(record[0])
-	versionStr := strings.TrimSpace(record[1])
-
-	// Remove "=" prefix and parse version
-	// Handle "= 0.0.7" or "= 3.12.5 || = 3.12.6"
-	versionStr = strings.TrimPrefix(versionStr, "=")
-	versionStr = strings.TrimSpace(versionStr)
-
-	// For now, take the first version if multiple are specified
-	if strings.Contains(versionStr, "||") {
-		parts := strings.Split(versionStr, "||")
-		versionStr = strings.TrimSpace(parts[0])
-		versionStr = strings.TrimPrefix(versionStr, "=")
-		versionStr = strings.TrimSpace(versionStr)
-	}
-
-	return BlocklistEntry{
-		PackageName: packageName,
-		Version:     versionStr,
-		Severity:    SeverityCritical, // Wiz lists are all compromised packages
-		Reason:      "Compromised package (Shai-Hulud attack)",
-		CVE:         "",
 	}
 }
 
 // IsBlocked checks if a specific package version is in the blocklist
+// Entries that differ in how they list the version (say an exact version from
+// one source and a range from another) can all match; they are then merged
+// like MergeBlocklists merges duplicates, so every source is cited.
 func (b *Blocklist) IsBlocked(packageName, version string) *BlocklistEntry {
 	// Use index to find entries for this package
 	indices, exists := b.Index[packageName]
@@ -134,40 +125,76 @@ func (b *Blocklist) IsBlocked(packageName, version string) *BlocklistEntry {
 	}
 
 	// Check each entry for this package
+	matches := make([]int, 0, 1)
 	for _, idx := range indices {
-		entry := &b.Entries[idx]
-		if entry.Version == version {
-			return entry // Found a match!
+		if b.Entries[idx].Matches(version) {
+			matches = append(matches, idx)
 		}
 	}
 
-	return nil // Package exists in blocklist but not this version
+	switch len(matches) {
+	case 0:
+		return nil // Package exists in blocklist but not this version
+	case 1:
+		return &b.Entries[matches[0]]
+	}
+
+	merged := copyEntry(b.Entries[matches[0]])
+	for _, idx := range matches[1:] {
+		mergeEntry(&merged, b.Entries[idx])
+	}
+	return &merged
 }
 
+// maxFindingPaths caps the chains recorded on a finding
+const maxFindingPaths = 100
+
 // ScanGraph scans a dependency graph against a blocklist
 func ScanGraph(g *graph.Graph, blocklist *Blocklist) *ScanResult {
+	return ScanGraphNodes(g, blocklist, func(*graph.Node) bool { return true })
+}
+
+// ScanGraphNodes is ScanGraph for the packages include accepts only
+// Skipped packages are neither counted nor checked, so no chains are built for them.
+func ScanGraphNodes(g *graph.Graph, blocklist *Blocklist, include func(*graph.Node) bool) *ScanResult {
 	result := &ScanResult{
 		Findings:      make([]Finding, 0),
-		TotalPackages: len(g.Nodes),
+		TotalPackages: 0,
 		IssuesFound:   0,
 	}
 
 	// Scan each package in the graph
 	for path, node := range g.Nodes {
+		if !include(node) {
+			continue
+		}
+		result.TotalPackages++
 		pkg := node.Package
 
 		// Check if this package/version is blocklisted
 		entry := blocklist.IsBlocked(pkg.Name, pkg.Version)
 		if entry != nil {
 			// Found a compromised package!
+			paths, truncated := g.AllPaths(node, maxFindingPaths)
 			finding := Finding{
-				PackageName: pkg.Name,
-				Version:     pkg.Version,
-				Path:        g.FindPath(path),
-				Severity:    entry.Severity,
-				Reason:      entry.Reason,
-				CVE:         entry.CVE,
-				IsDirect:    node.IsDirect,
+				RuleID:         RuleCompromisedPackage,
+				PackageName:    pkg.Name,
+				Version:        pkg.Version,
+				PackagePath:    path,
+				Path:           g.FindPath(path),
+				Paths:          dependencyPaths(paths),
+				PathsTruncated: truncated,
+				EntryPoints:    packageNames(g.EntryPoints(node)),
+				Severity:       entry.Severity,
+				Reason:         entry.Reason,
+				Identifiers:    entry.Identifiers,
+				CVSSScore:      entry.CVSSScore,
+				FixedIn:        entry.FixedIn,
+				References:     entry.References,
+				Tags:           entry.Tags,
+				Sources:        entry.Sources,
+				IsDirect:       node.IsDirect,
+				Line:           pkg.Line,
 			}
 
 			result.Findings = append(result.Findings, finding)
@@ -177,3 +204,21 @@ func ScanGraph(g *graph.Graph, blocklist *Blocklist) *ScanResult {
 
 	return result
 }
+
+// dependencyPaths converts node chains into package name chains
+func dependencyPaths(paths []graph.NodePath) []graph.DependencyPath {
+	names := make([]graph.DependencyPath, 0, len(paths))
+	for _, path := range paths {
+		names = append(names, path.Names())
+	}
+	return names
+}
+
+// packageNames returns the package names of nodes
+func packageNames(nodes []*graph.Node) []string {
+	names := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		names = append(names, node.Package.Name)
+	}
+	return names
+}
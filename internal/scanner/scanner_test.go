@@ -84,6 +84,73 @@ func TestScanGraph_WithCompromisedPackage(t *testing.T) {
 	assert.Contains(t, finding.Reason, "Prototype pollution")
 }
 
+func TestScanGraphNodes(t *testing.T) {
+	// Arrange - two blocklisted packages, only one of them selected
+	lockfile := &parser.Lockfile{
+		Name:               "test-app",
+		DirectDependencies: map[string]string{"lodash": "4.17.20", "evil": "1.0.0"},
+		Packages: map[string]*parser.Package{
+			"node_modules/lodash": {Name: "lodash", Version: "4.17.20"},
+			"node_modules/evil":   {Name: "evil", Version: "1.0.0"},
+		},
+	}
+	g, err := graph.BuildGraph(lockfile)
+	require.NoError(t, err)
+	blocklist := newBlocklist([]BlocklistEntry{
+		{PackageName: "lodash", Version: "4.17.20", Severity: SeverityHigh},
+		{PackageName: "evil", Version: "1.0.0", Severity: SeverityCritical},
+	})
+
+	// Act
+	result := ScanGraphNodes(g, blocklist, func(node *graph.Node) bool {
+		return node.Package.Name == "evil"
+	})
+
+	// Assert
+	assert.Equal(t, 1, result.TotalPackages)
+	require.Len(t, result.Findings, 1)
+	assert.Equal(t, "evil", result.Findings[0].PackageName)
+}
+
+func TestScanGraph_AllPaths(t *testing.T) {
+	// Arrange - lodash is reached through both express and koa
+	lockfile := &parser.Lockfile{
+		Name:    "test-app",
+		Version: "1.0.0",
+		DirectDependencies: map[string]string{
+			"express": "4.18.2",
+			"koa":     "2.14.0",
+			"debug":   "4.3.4",
+		},
+		Packages: map[string]*parser.Package{
+			"node_modules/express": {Name: "express", Version: "4.18.2", Dependencies: map[string]string{"lodash": "4.17.20"}},
+			"node_modules/koa":     {Name: "koa", Version: "2.14.0", Dependencies: map[string]string{"debug": "4.3.4"}},
+			"node_modules/debug":   {Name: "debug", Version: "4.3.4", Dependencies: map[string]string{"lodash": "4.17.20"}},
+			"node_modules/lodash":  {Name: "lodash", Version: "4.17.20"},
+		},
+	}
+	g, err := graph.BuildGraph(lockfile)
+	require.NoError(t, err)
+
+	blocklist, err := LoadBlocklist("../../testdata/sample-blocklist.csv")
+	require.NoError(t, err)
+
+	// Act
+	result := ScanGraph(g, blocklist)
+
+	// Assert
+	require.Len(t, result.Findings, 1)
+	finding := result.Findings[0]
+	assert.Equal(t, []graph.DependencyPath{
+		{"test-app", "debug", "lodash"},
+		{"test-app", "express", "lodash"},
+		{"test-app", "koa", "debug", "lodash"},
+	}, finding.Paths)
+	assert.False(t, finding.PathsTruncated)
+	assert.Equal(t, []string{"debug", "express", "koa"}, finding.EntryPoints)
+	assert.Len(t, finding.Path, 3, "Path stays the shortest chain")
+}
+
 func TestScanGraph_CleanPackages(t *testing.T) {
 	// Test with packages not in blocklist
 	lockfile := &parser.Lockfile{
@@ -158,3 +225,28 @@ func TestBlocklist_IsBlocked(t *testing.T) {
 		})
 	}
 }
+
+func TestBlocklist_IsBlocked_MergesSources(t *testing.T) {
+	// Arrange - an exact version from one feed and a range from another
+	wiz := newBlocklist([]BlocklistEntry{{
+		PackageName: "evil", Version: "1.0.1", Severity: SeverityHigh,
+		Identifiers: []string{"WIZ-1"}, Sources: []string{"wiz.csv"},
+	}})
+	osv := newBlocklist([]BlocklistEntry{{
+		PackageName: "evil", Ranges: []VersionRange{{Introduced: "1.0.0", Fixed: "1.0.2"}}, Severity: SeverityCritical,
+		Reason: "Worm", Identifiers: []string{"MAL-1"}, Sources: []string{"osv.zip"},
+	}})
+	blocklist := MergeBlocklists(wiz, osv)
+
+	// Act
+	entry := blocklist.IsBlocked("evil", "1.0.1")
+
+	// Assert
+	require.NotNil(t, entry)
+	assert.Equal(t, []string{"wiz.csv", "osv.zip"}, entry.Sources)
+	assert.Equal(t, []string{"WIZ-1", "MAL-1"}, entry.Identifiers)
+	assert.Equal(t, SeverityCritical, entry.Severity)
+	assert.Equal(t, "Worm", entry.Reason)
+	assert.Equal(t, []string{"wiz.csv"}, blocklist.Entries[0].Sources, "the stored entries are left alone")
+	assert.Equal(t, []string{"osv.zip"}, blocklist.IsBlocked("evil", "1.0.0").Sources)
+}
@@ -0,0 +1,195 @@
+package scanner
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Source describes one blocklist to load
+// In the config file a source is either a plain string or a mapping:
+//
+//	blocklists:
+//	  - https://example.com/list.csv
+//	  - url: ./internal-blocklist.csv
+//	  - url: https://example.com/hourly.csv
+//	    ttl: 10m
+//	  - url: https://example.com/iocs.csv
+//	    severity: high
+//	    reason: Listed by the example.com IOC feed
+//	    tags: [example-campaign]
+type Source struct {
+	Location string        `yaml:"url"`      // URL or local file path
+	TTL      time.Duration `yaml:"ttl"`      // Cache lifetime for this source (0 uses the default)
+	Severity Severity      `yaml:"severity"` // Severity for entries that have none (default critical)
+	Reason   string        `yaml:"reason"`   // Reason for entries that have none
+	Tags     []string      `yaml:"tags"`     // Tags added to every entry (e.g. the campaign)
+	Auth     *Auth         `yaml:"auth"`     // Credentials for a protected URL
+}
+
+// defaultReason is used for entries that give no reason when their source sets none
+const defaultReason = "Listed as a compromised package"
+
+// applyDefaults fills in the severity and reason of entries that have none and
+// adds the source's tags; parsers leave those fields empty so a source can decide
+func (s Source) applyDefaults(entries []BlocklistEntry) {
+	severity := s.Severity
+	if severity == "" {
+		severity = SeverityCritical
+	}
+	reason := s.Reason
+	if reason == "" {
+		reason = defaultReason
+	}
+
+	for i := range entries {
+		if entries[i].Severity == "" {
+			entries[i].Severity = severity
+		}
+		if entries[i].Reason == "" {
+			entries[i].Reason = reason
+		}
+		if len(s.Tags) > 0 {
+			entries[i].Tags = appendUnique(entries[i].Tags, s.Tags...)
+		}
+	}
+}
+
+// UnmarshalYAML accepts both the scalar and the mapping form of a source
+func (s *Source) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		s.Location = node.Value
+		return nil
+	}
+
+	// Decode through an alias type to avoid recursing into this method
+	type plain Source
+	var decoded plain
+	if err := node.Decode(&decoded); err != nil {
+		return err
+	}
+	*s = Source(decoded)
+
+	s.Severity = Severity(strings.ToLower(string(s.Severity)))
+	if s.Severity != "" && severityRank(s.Severity) == 0 {
+		return fmt.Errorf("blocklist %s: unknown severity %q (want critical, high, medium, low or info)", s.Location, s.Severity)
+	}
+	return nil
+}
+
+// LoadBlocklists loads every source concurrently and merges them into one blocklist
+// All sources are required: if any of them fails, the whole load fails.
+func LoadBlocklists(sources []Source, cacheDir string) (*Blocklist, error) {
+	loader := &Loader{CacheDir: cacheDir}
+	return loader.LoadAll(sources)
+}
+
+// MergeBlocklists combines blocklists, de-duplicating entries by package and version
+// When several lists flag the same version, their sources are combined and the
+// most severe rating wins, identifiers, fixed versions, references, hashes and
+// tags are combined, the earliest publication date is kept, and the reason and
+// CVSS data come from the first list that has them.
+func MergeBlocklists(lists ...*Blocklist) *Blocklist {
+	entries := make([]BlocklistEntry, 0)
+	seen := make(map[string]int) // "name@version" -> index in entries
+
+	for _, list := range lists {
+		if list == nil {
+			continue
+		}
+
+		for _, entry := range list.Entries {
+			key := entryKey(entry)
+
+			idx, exists := seen[key]
+			if !exists {
+				seen[key] = len(entries)
+				entries = append(entries, copyEntry(entry))
+				continue
+			}
+			mergeEntry(&entries[idx], entry)
+		}
+	}
+
+	return newBlocklist(entries)
+}
+
+// copyEntry returns entry with its lists copied, so merging into it leaves the original alone
+func copyEntry(entry BlocklistEntry) BlocklistEntry {
+	entry.Sources = append([]string(nil), entry.Sources...)
+	entry.Identifiers = append([]string(nil), entry.Identifiers...)
+	entry.FixedIn = append([]string(nil), entry.FixedIn...)
+	entry.References = append([]string(nil), entry.References...)
+	entry.Hashes = append([]string(nil), entry.Hashes...)
+	entry.Tags = append([]string(nil), entry.Tags...)
+	return entry
+}
+
+// mergeEntry folds entry into merged the way MergeBlocklists combines duplicates
+func mergeEntry(merged *BlocklistEntry, entry BlocklistEntry) {
+	merged.Sources = appendUnique(merged.Sources, entry.Sources...)
+	merged.Identifiers = appendUnique(merged.Identifiers, entry.Identifiers...)
+	merged.FixedIn = appendUnique(merged.FixedIn, entry.FixedIn...)
+	merged.References = appendUnique(merged.References, entry.References...)
+	merged.Hashes = appendUnique(merged.Hashes, entry.Hashes...)
+	merged.Tags = appendUnique(merged.Tags, entry.Tags...)
+	if !entry.Published.IsZero() && (merged.Published.IsZero() || entry.Published.Before(merged.Published)) {
+		merged.Published = entry.Published
+	}
+	if severityRank(entry.Severity) > severityRank(merged.Severity) {
+		merged.Severity = entry.Severity
+	}
+	if merged.Reason == "" {
+		merged.Reason = entry.Reason
+	}
+	if merged.CVSSScore == 0 {
+		merged.CVSSScore = entry.CVSSScore
+		merged.CVSSVector = entry.CVSSVector
+	}
+}
+
+// entryKey identifies entries that describe the same affected versions
+func entryKey(entry BlocklistEntry) string {
+	key := entry.PackageName + "@" + entry.Version
+	for _, r := range entry.Ranges {
+		key += "|" + r.String()
+	}
+	return key
+}
+
+// severityRank orders severities so they can be compared
+func severityRank(severity Severity) int {
+	switch severity {
+	case SeverityCritical:
+		return 5
+	case SeverityHigh:
+		return 4
+	case SeverityMedium:
+		return 3
+	case SeverityLow:
+		return 2
+	case SeverityInfo:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// appendUnique appends values that are not already present
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		found := false
+		for _, existing := range list {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
@@ -0,0 +1,118 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMergeBlocklists(t *testing.T) {
+	// Arrange - two lists that both flag lodash@4.17.20
+	wiz := newBlocklist([]BlocklistEntry{
+		{PackageName: "lodash", Version: "4.17.20", Severity: SeverityHigh, Reason: "Compromised", Sources: []string{"wiz"}},
+		{PackageName: "02-echo", Version: "0.0.7", Severity: SeverityCritical, Reason: "Shai-Hulud", Sources: []string{"wiz"}},
+	})
+	internal := newBlocklist([]BlocklistEntry{
+		{PackageName: "lodash", Version: "4.17.20", Severity: SeverityCritical, Identifiers: []string{"CVE-2020-8203"}, Sources: []string{"internal"}},
+		{PackageName: "lodash", Version: "4.17.19", Severity: SeverityLow, Sources: []string{"internal"}},
+	})
+
+	// Act
+	merged := MergeBlocklists(wiz, internal)
+
+	// Assert
+	assert.Len(t, merged.Entries, 3, "duplicates should be merged")
+
+	entry := merged.IsBlocked("lodash", "4.17.20")
+	require.NotNil(t, entry)
+	assert.Equal(t, []string{"wiz", "internal"}, entry.Sources)
+	assert.Equal(t, SeverityCritical, entry.Severity, "most severe rating wins")
+	assert.Equal(t, "Compromised", entry.Reason, "first reason is kept")
+	assert.Equal(t, []string{"CVE-2020-8203"}, entry.Identifiers, "identifiers are combined")
+
+	assert.NotNil(t, merged.IsBlocked("lodash", "4.17.19"))
+	assert.NotNil(t, merged.IsBlocked("02-echo", "0.0.7"))
+
+	// Inputs are left untouched
+	assert.Equal(t, []string{"wiz"}, wiz.Entries[0].Sources)
+}
+
+func TestLoadBlocklists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("Package,Version\nlodash,= 4.17.20\nleft-pad,= 1.3.0\n"))
+	}))
+	defer server.Close()
+
+	sources := []Source{
+		{Location: "../../testdata/sample-blocklist.csv"},
+		{Location: server.URL},
+	}
+
+	blocklist, err := LoadBlocklists(sources, "")
+
+	require.NoError(t, err)
+	assert.Len(t, blocklist.Entries, 6, "5 local entries + left-pad")
+
+	lodash := blocklist.IsBlocked("lodash", "4.17.20")
+	require.NotNil(t, lodash)
+	assert.Equal(t, []string{"../../testdata/sample-blocklist.csv", server.URL}, lodash.Sources)
+
+	leftPad := blocklist.IsBlocked("left-pad", "1.3.0")
+	require.NotNil(t, leftPad)
+	assert.Equal(t, []string{server.URL}, leftPad.Sources)
+}
+
+func TestLoadBlocklists_Errors(t *testing.T) {
+	_, err := LoadBlocklists(nil, "")
+	assert.Error(t, err)
+
+	_, err = LoadBlocklists([]Source{
+		{Location: "../../testdata/sample-blocklist.csv"},
+		{Location: "../../testdata/missing.csv"},
+	}, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing.csv")
+}
+
+func TestSource_UnmarshalYAML(t *testing.T) {
+	var sources []Source
+	err := yaml.Unmarshal([]byte("- https://a.example/list.csv\n- url: ./b.csv\n"), &sources)
+
+	require.NoError(t, err)
+	assert.Equal(t, []Source{{Location: "https://a.example/list.csv"}, {Location: "./b.csv"}}, sources)
+}
+
+func TestLoadBlocklists_SourceDefaults(t *testing.T) {
+	// Arrange - a bare IOC list, plus a full list that rates its own entries
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("Package,Version\nleft-pad,= 1.3.0\n"))
+	}))
+	defer server.Close()
+
+	sources := []Source{
+		{Location: server.URL, Severity: SeverityHigh, Reason: "Internal IOC feed", Tags: []string{"campaign-x"}},
+		{Location: "../../testdata/sample-blocklist.csv", Reason: "never used", Tags: []string{"sample"}},
+	}
+
+	// Act
+	blocklist, err := LoadBlocklists(sources, "")
+
+	// Assert
+	require.NoError(t, err)
+
+	leftPad := blocklist.IsBlocked("left-pad", "1.3.0")
+	require.NotNil(t, leftPad)
+	assert.Equal(t, SeverityHigh, leftPad.Severity)
+	assert.Equal(t, "Internal IOC feed", leftPad.Reason)
+	assert.Equal(t, []string{"campaign-x"}, leftPad.Tags)
+
+	lodash := blocklist.IsBlocked("lodash", "4.17.20")
+	require.NotNil(t, lodash)
+	assert.Equal(t, SeverityCritical, lodash.Severity, "the list's own severity wins")
+	assert.Contains(t, lodash.Reason, "Prototype pollution")
+	assert.Equal(t, []string{"sample"}, lodash.Tags)
+}
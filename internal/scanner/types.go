@@ -1,6 +1,11 @@
 package scanner
 
-import "github.com/fullstack-spiderman/hulud-scan/internal/graph"
+import (
+	"strings"
+	"time"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/graph"
+)
 
 // Severity levels for security findings
 type Severity string
@@ -14,12 +19,31 @@ const (
 )
 
 // BlocklistEntry represents a known compromised package version
+// An entry matches either one exact Version or any version inside its Ranges.
 type BlocklistEntry struct {
-	PackageName string   // Name of the compromised package
-	Version     string   // Affected version
-	Severity    Severity // How serious is this?
-	Reason      string   // Why is it flagged?
-	CVE         string   // CVE identifier (if applicable)
+	PackageName string         // Name of the compromised package
+	Version     string         // Affected version (empty when Ranges is used)
+	Ranges      []VersionRange // Affected version ranges (e.g. from OSV)
+	Severity    Severity       // How serious is this?
+	Reason      string         // Why is it flagged?
+	Identifiers []string       // Advisory identifiers (CVE, GHSA, MAL, ...)
+	CVSSScore   float64        // CVSS base score (0 if unknown)
+	CVSSVector  string         // CVSS vector string (if published)
+	FixedIn     []string       // Versions that fix the issue (if any)
+	References  []string       // Advisory and write-up links
+	Published   time.Time      // When the entry was published (zero if unknown)
+	Hashes      []string       // IOC hashes of malicious files, as "algorithm:hex"
+	Tags        []string       // Campaign or category tags (e.g. "shai-hulud-2")
+	Sources     []string       // Blocklist sources (URL or path) that list this entry
+}
+
+// VersionRange is a span of affected versions in OSV terms
+// Introduced is inclusive ("" or "0" means every earlier version); Fixed is
+// exclusive and LastAffected inclusive. Both empty means no upper bound.
+type VersionRange struct {
+	Introduced   string
+	Fixed        string
+	LastAffected string
 }
 
 // Blocklist is a collection of known compromised packages
@@ -30,13 +54,49 @@ type Blocklist struct {
 
 // Finding represents a security issue found during scanning
 type Finding struct {
-	PackageName string               // Package that was flagged
-	Version     string               // Version that was flagged
-	Path        graph.DependencyPath // How we got to this package
-	Severity    Severity             // Severity of the issue
-	Reason      string               // Why it was flagged
-	CVE         string               // CVE if applicable
-	IsDirect    bool                 // Is this a direct dependency?
+	RuleID         string                 // Check that produced it (see Rules); "" means RuleCompromisedPackage
+	PackageName    string                 // Package that was flagged
+	Version        string                 // Version that was flagged
+	PackagePath    string                 // Package path in the lockfile (e.g. node_modules/express)
+	Path           graph.DependencyPath   // How we got to this package
+	Paths          []graph.DependencyPath // Every chain to this package, shortest first (capped at 100)
+	PathsTruncated bool                   // More chains exist than Paths lists
+	EntryPoints    []string               // Direct dependencies that lead to this package
+	Severity       Severity               // Severity of the issue
+	Reason         string                 // Why it was flagged
+	Identifiers    []string               // Advisory identifiers (CVE, GHSA, MAL, ...)
+	CVSSScore      float64                // CVSS base score (0 if unknown)
+	FixedIn        []string               // Versions that fix the issue (if any)
+	References     []string               // Advisory and write-up links
+	Tags           []string               // Campaign or category tags (e.g. "shai-hulud-2")
+	Sources        []string               // Blocklist sources that flagged this package
+	IsDirect       bool                   // Is this a direct dependency?
+	Line           int                    // Line of the package entry in the lockfile (0 if unknown)
+}
+
+// CVE returns the first CVE identifier of the entry, or "" if there is none
+func (e *BlocklistEntry) CVE() string {
+	return firstCVE(e.Identifiers)
+}
+
+// CVE returns the first CVE identifier of the finding, or "" if there is none
+func (f Finding) CVE() string {
+	return firstCVE(f.Identifiers)
+}
+
+// Rule returns the check that produced the finding
+func (f Finding) Rule() Rule {
+	return LookupRule(f.RuleID)
+}
+
+// firstCVE picks the first CVE out of a list of identifiers
+func firstCVE(identifiers []string) string {
+	for _, id := range identifiers {
+		if strings.HasPrefix(strings.ToUpper(id), "CVE-") {
+			return id
+		}
+	}
+	return ""
 }
 
 // ScanResult contains all findings from a scan
@@ -0,0 +1,134 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/semver"
+)
+
+// ValidationIssue is one problem found in a blocklist file
+type ValidationIssue struct {
+	Line    int    // 1-based line in the file (0 when not tied to a line)
+	Message string // What is wrong
+}
+
+// String formats the issue as "line N: message"
+func (i ValidationIssue) String() string {
+	if i.Line == 0 {
+		return i.Message
+	}
+	return fmt.Sprintf("line %d: %s", i.Line, i.Message)
+}
+
+// ValidateBlocklist checks blocklist data and reports every problem it finds
+// Loading stops at the first error or silently skips rows it cannot use;
+// validation keeps going so a whole file can be fixed in one pass. CSV files
+// are checked row by row; other formats are reported as a whole.
+func ValidateBlocklist(name string, data []byte) []ValidationIssue {
+	if DetectFormat(name, data) == FormatCSV {
+		return validateBlocklistCSV(data)
+	}
+
+	blocklist, err := parseBlocklistData(name, data)
+	if err != nil {
+		return []ValidationIssue{{Message: err.Error()}}
+	}
+	if len(blocklist.Entries) == 0 {
+		return []ValidationIssue{{Message: "blocklist has no npm entries"}}
+	}
+	return nil
+}
+
+// validateBlocklistCSV checks each CSV row the way parseBlocklistCSV reads it
+func validateBlocklistCSV(data []byte) []ValidationIssue {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1 // Report column mismatches ourselves
+
+	issues := make([]ValidationIssue, 0)
+	var header []string
+	var layout *csvLayout
+	seen := make(map[string]int) // "name@version" -> first line
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				issues = append(issues, ValidationIssue{Line: parseErr.Line, Message: parseErr.Err.Error()})
+				continue
+			}
+			issues = append(issues, ValidationIssue{Message: err.Error()})
+			break
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		if header == nil {
+			header = record
+			if layout, err = newCSVLayout(header); err != nil {
+				issues = append(issues, ValidationIssue{Line: line, Message: err.Error() +
+					"; expected \"package_name,version,severity,reason[,cve]\", \"Package,Version\" or named columns"})
+				return issues
+			}
+			continue
+		}
+
+		if len(record) != len(header) {
+			issues = append(issues, ValidationIssue{Line: line, Message: fmt.Sprintf(
+				"expected %d columns, got %d", len(header), len(record))})
+			if len(record) <= layout.columns[columnVersion] {
+				continue // parseBlocklistCSV skips these rows entirely
+			}
+		}
+
+		entries, problems := layout.entries(record)
+		for _, entry := range entries {
+			for _, message := range validateEntry(entry) {
+				issues = append(issues, ValidationIssue{Line: line, Message: message})
+			}
+
+			key := entry.PackageName + "@" + entry.Version
+			if first, ok := seen[key]; ok {
+				issues = append(issues, ValidationIssue{Line: line, Message: fmt.Sprintf(
+					"duplicate entry %s (first on line %d)", key, first)})
+			} else {
+				seen[key] = line
+			}
+		}
+
+		// Loading drops these cells; here they are errors
+		for _, problem := range problems {
+			issues = append(issues, ValidationIssue{Line: line, Message: problem})
+		}
+	}
+
+	if header == nil {
+		issues = append(issues, ValidationIssue{Message: "blocklist file is empty or missing header"})
+	}
+
+	return issues
+}
+
+// validateEntry checks the fields of one parsed entry
+func validateEntry(entry BlocklistEntry) []string {
+	messages := make([]string, 0)
+
+	if entry.PackageName == "" {
+		messages = append(messages, "missing package name")
+	}
+
+	if entry.Version == "" {
+		messages = append(messages, "missing version")
+	} else if _, err := semver.Parse(entry.Version); err != nil {
+		messages = append(messages, fmt.Sprintf("version %q is not semver and can only match exactly", entry.Version))
+	}
+
+	return messages
+}
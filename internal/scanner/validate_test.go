@@ -0,0 +1,84 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateBlocklist_CSV(t *testing.T) {
+	// Arrange - one problem per row after the valid first entry
+	data := []byte(`package_name,version,severity,reason,cve
+lodash,4.17.20,critical,Prototype pollution,CVE-2020-8203
+,1.0.0,high,No name
+foo,,urgent,No version,
+lodash,4.17.20,critical,Again,
+bar,1.0,low,Short version,
+`)
+
+	// Act
+	issues := ValidateBlocklist("blocklist.csv", data)
+
+	// Assert
+	expected := []ValidationIssue{
+		{Line: 3, Message: "expected 5 columns, got 4"},
+		{Line: 3, Message: "missing package name"},
+		{Line: 4, Message: "missing version"},
+		{Line: 4, Message: `unknown severity "urgent" (want critical, high, medium, low or info)`},
+		{Line: 5, Message: "duplicate entry lodash@4.17.20 (first on line 2)"},
+		{Line: 6, Message: `version "1.0" is not semver and can only match exactly`},
+	}
+	assert.Equal(t, expected, issues)
+}
+
+func TestValidateBlocklist_CSVBadCells(t *testing.T) {
+	data := []byte("package,version,cvss_score,published\nevil,1.0.0,high,yesterday\n")
+
+	issues := ValidateBlocklist("blocklist.csv", data)
+
+	assert.Equal(t, []ValidationIssue{
+		{Line: 2, Message: `invalid CVSS score "high"`},
+		{Line: 2, Message: `invalid published date "yesterday" (want YYYY-MM-DD or RFC 3339)`},
+	}, issues, "loading drops these cells; validation reports them")
+}
+
+func TestValidateBlocklist_Valid(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"full CSV", "../../testdata/sample-blocklist.csv"},
+		{"native YAML", "../../testdata/blocklists/shai-hulud-2.yaml"},
+		{"native JSON", "../../testdata/blocklists/shai-hulud-2.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := readTestFile(t, tt.path)
+			assert.Empty(t, ValidateBlocklist(tt.path, data))
+		})
+	}
+}
+
+func TestValidateBlocklist_WizFormat(t *testing.T) {
+	data := []byte("Package,Version\nlodash,= 4.17.20\nexpress\n")
+
+	issues := ValidateBlocklist("wiz.csv", data)
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, ValidationIssue{Line: 3, Message: "expected 2 columns, got 1"}, issues[0])
+}
+
+func TestValidateBlocklist_NativeError(t *testing.T) {
+	issues := ValidateBlocklist("list.yaml", []byte("schema_version: 1\nentries:\n  - package: evil\n"))
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, 0, issues[0].Line)
+	assert.Contains(t, issues[0].String(), "needs versions or ranges")
+}
+
+func TestValidationIssue_String(t *testing.T) {
+	assert.Equal(t, "line 4: missing version", ValidationIssue{Line: 4, Message: "missing version"}.String())
+	assert.Equal(t, "empty", ValidationIssue{Message: "empty"}.String())
+}
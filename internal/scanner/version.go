@@ -0,0 +1,87 @@
+package scanner
+
+import (
+	"strings"
+
+	"github.com/fullstack-spiderman/hulud-scan/internal/semver"
+)
+
+// Matches reports whether version is affected by this entry
+func (e *BlocklistEntry) Matches(version string) bool {
+	if e.Version != "" && e.Version == version {
+		return true
+	}
+
+	if len(e.Ranges) == 0 {
+		return false
+	}
+
+	// Range checks need a parseable version; anything else can only match exactly
+	v, err := semver.Parse(version)
+	if err != nil {
+		return false
+	}
+
+	for _, r := range e.Ranges {
+		if r.contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// contains reports whether v falls inside the range
+func (r VersionRange) contains(v semver.Version) bool {
+	if r.Introduced != "" && r.Introduced != "0" {
+		introduced, err := semver.Parse(r.Introduced)
+		if err != nil || v.Compare(introduced) < 0 {
+			return false
+		}
+	}
+
+	if r.Fixed != "" {
+		fixed, err := semver.Parse(r.Fixed)
+		if err != nil || v.Compare(fixed) >= 0 {
+			return false
+		}
+	}
+
+	if r.LastAffected != "" {
+		last, err := semver.Parse(r.LastAffected)
+		if err != nil || v.Compare(last) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String formats the range for display (e.g. ">=1.0.0 <1.2.3")
+func (r VersionRange) String() string {
+	parts := make([]string, 0, 2)
+	if r.Introduced != "" && r.Introduced != "0" {
+		parts = append(parts, ">="+r.Introduced)
+	}
+	if r.Fixed != "" {
+		parts = append(parts, "<"+r.Fixed)
+	}
+	if r.LastAffected != "" {
+		parts = append(parts, "<="+r.LastAffected)
+	}
+	if len(parts) == 0 {
+		return "*"
+	}
+	return strings.Join(parts, " ")
+}
+
+// Affected describes the versions an entry covers (e.g. "1.0.0" or ">=1.0.0 <1.2.3")
+func (e *BlocklistEntry) Affected() string {
+	parts := make([]string, 0, len(e.Ranges)+1)
+	if e.Version != "" {
+		parts = append(parts, e.Version)
+	}
+	for _, r := range e.Ranges {
+		parts = append(parts, r.String())
+	}
+	return strings.Join(parts, " || ")
+}
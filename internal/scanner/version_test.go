@@ -0,0 +1,37 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlocklistEntry_Matches(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    BlocklistEntry
+		version  string
+		expected bool
+	}{
+		{"exact match", BlocklistEntry{Version: "1.0.0"}, "1.0.0", true},
+		{"exact mismatch", BlocklistEntry{Version: "1.0.0"}, "1.0.1", false},
+		{"all versions", BlocklistEntry{Ranges: []VersionRange{{Introduced: "0"}}}, "9.9.9", true},
+		{"below introduced", BlocklistEntry{Ranges: []VersionRange{{Introduced: "2.0.0"}}}, "1.9.9", false},
+		{"fixed is exclusive", BlocklistEntry{Ranges: []VersionRange{{Fixed: "2.0.0"}}}, "2.0.0", false},
+		{"prerelease before fixed", BlocklistEntry{Ranges: []VersionRange{{Fixed: "2.0.0"}}}, "2.0.0-rc.1", true},
+		{"last affected is inclusive", BlocklistEntry{Ranges: []VersionRange{{LastAffected: "2.0.0"}}}, "2.0.0", true},
+		{"unparseable version", BlocklistEntry{Ranges: []VersionRange{{Introduced: "0"}}}, "latest", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.entry.Matches(tt.version))
+		})
+	}
+}
+
+func TestVersionRange_String(t *testing.T) {
+	assert.Equal(t, "*", VersionRange{Introduced: "0"}.String())
+	assert.Equal(t, ">=1.0.0 <1.2.3", VersionRange{Introduced: "1.0.0", Fixed: "1.2.3"}.String())
+	assert.Equal(t, "<=4.1.2", VersionRange{LastAffected: "4.1.2"}.String())
+}
@@ -0,0 +1,316 @@
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Range is an npm version range such as "^1.2.3", ">=1.0.0 <2.0.0" or "1.x || 2.x"
+// See https://github.com/npm/node-semver#ranges for the grammar.
+type Range struct {
+	sets [][]comparator // Alternatives joined by "||"; each is a list of comparators that must all hold
+}
+
+// comparator is a single "op version" condition
+type comparator struct {
+	op      string // One of <, <=, >, >=, =
+	version Version
+}
+
+// partial is a version whose trailing parts may be missing or wildcards (-1)
+type partial struct {
+	major, minor, patch int
+	prerelease          []string
+}
+
+// ParseRange parses an npm version range
+// Specs that are not ranges (dist-tags, git URLs, file: and npm: specs) are rejected.
+func ParseRange(s string) (Range, error) {
+	var r Range
+	for _, alternative := range strings.Split(s, "||") {
+		set, err := parseComparatorSet(strings.TrimSpace(alternative))
+		if err != nil {
+			return Range{}, fmt.Errorf("invalid range %q: %w", s, err)
+		}
+		r.sets = append(r.sets, set)
+	}
+	return r, nil
+}
+
+// Contains reports whether v is in the range
+// As in npm, a pre-release version only matches when a comparator of the same
+// alternative names a pre-release of the same major.minor.patch.
+func (r Range) Contains(v Version) bool {
+	for _, set := range r.sets {
+		if setContains(set, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Satisfies parses a version and a range and reports whether the range contains the version
+func Satisfies(version, rng string) (bool, error) {
+	v, err := Parse(version)
+	if err != nil {
+		return false, err
+	}
+	r, err := ParseRange(rng)
+	if err != nil {
+		return false, err
+	}
+	return r.Contains(v), nil
+}
+
+// setContains checks one alternative of a range
+func setContains(set []comparator, v Version) bool {
+	for _, c := range set {
+		if !c.matches(v) {
+			return false
+		}
+	}
+	if len(v.Prerelease) == 0 {
+		return true
+	}
+
+	for _, c := range set {
+		if len(c.version.Prerelease) > 0 && c.version.Major == v.Major &&
+			c.version.Minor == v.Minor && c.version.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+// matches applies the comparator to v
+func (c comparator) matches(v Version) bool {
+	cmp := v.Compare(c.version)
+	switch c.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	default:
+		return cmp == 0
+	}
+}
+
+// parseComparatorSet parses one alternative: a hyphen range or space-separated comparators
+func parseComparatorSet(s string) ([]comparator, error) {
+	tokens := rangeTokens(s)
+	if len(tokens) == 0 {
+		return []comparator{{op: ">=", version: Version{}}}, nil // "" matches any release
+	}
+
+	if len(tokens) == 3 && tokens[1] == "-" {
+		return parseHyphenRange(tokens[0], tokens[2])
+	}
+
+	set := make([]comparator, 0, len(tokens))
+	for _, token := range tokens {
+		comparators, err := parseSimple(token)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, comparators...)
+	}
+	return set, nil
+}
+
+// rangeTokens splits a comparator set on whitespace, joining operators to their version ("> = 1" is not valid, ">= 1" is)
+func rangeTokens(s string) []string {
+	tokens := make([]string, 0)
+	pending := ""
+	for _, field := range strings.Fields(s) {
+		if strings.Trim(field, "<>=^~") == "" && field != "-" {
+			pending += field
+			continue
+		}
+		tokens = append(tokens, pending+field)
+		pending = ""
+	}
+	if pending != "" {
+		tokens = append(tokens, pending)
+	}
+	return tokens
+}
+
+// parseHyphenRange desugars "a - b" into ">=a <=b", widening partial bounds
+func parseHyphenRange(from, to string) ([]comparator, error) {
+	lower, err := parsePartial(from)
+	if err != nil {
+		return nil, err
+	}
+	upper, err := parsePartial(to)
+	if err != nil {
+		return nil, err
+	}
+
+	set := []comparator{{op: ">=", version: lower.floor()}}
+	switch {
+	case upper.major < 0:
+		// No upper bound
+	case upper.minor < 0:
+		set = append(set, comparator{op: "<", version: bumpFloor(upper.major+1, 0, 0)})
+	case upper.patch < 0:
+		set = append(set, comparator{op: "<", version: bumpFloor(upper.major, upper.minor+1, 0)})
+	default:
+		set = append(set, comparator{op: "<=", version: upper.floor()})
+	}
+	return set, nil
+}
+
+// parseSimple desugars one comparator, caret, tilde or x-range
+func parseSimple(token string) ([]comparator, error) {
+	op := ""
+	for _, prefix := range []string{"~>", ">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(token, prefix) {
+			op = prefix
+			break
+		}
+	}
+
+	p, err := parsePartial(strings.TrimPrefix(token, op))
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "^":
+		return caretRange(p), nil
+	case "~", "~>":
+		return tildeRange(p), nil
+	case "", "=":
+		return xRange(p), nil
+	default:
+		return primitive(op, p), nil
+	}
+}
+
+// caretRange allows changes that do not modify the left-most non-zero part
+func caretRange(p partial) []comparator {
+	lower := comparator{op: ">=", version: p.floor()}
+	switch {
+	case p.major < 0:
+		return []comparator{lower}
+	case p.major > 0 || p.minor < 0:
+		return []comparator{lower, {op: "<", version: bumpFloor(p.major+1, 0, 0)}}
+	case p.minor > 0 || p.patch < 0:
+		return []comparator{lower, {op: "<", version: bumpFloor(0, p.minor+1, 0)}}
+	default:
+		return []comparator{lower, {op: "<", version: bumpFloor(0, 0, p.patch+1)}}
+	}
+}
+
+// tildeRange allows patch changes, or minor changes when only the major is given
+func tildeRange(p partial) []comparator {
+	lower := comparator{op: ">=", version: p.floor()}
+	switch {
+	case p.major < 0:
+		return []comparator{lower}
+	case p.minor < 0:
+		return []comparator{lower, {op: "<", version: bumpFloor(p.major+1, 0, 0)}}
+	default:
+		return []comparator{lower, {op: "<", version: bumpFloor(p.major, p.minor+1, 0)}}
+	}
+}
+
+// xRange matches an exact version, or every version of the given parts ("1.2", "1.x", "*")
+func xRange(p partial) []comparator {
+	switch {
+	case p.major < 0:
+		return []comparator{{op: ">=", version: Version{}}}
+	case p.minor < 0:
+		return []comparator{{op: ">=", version: p.floor()}, {op: "<", version: bumpFloor(p.major+1, 0, 0)}}
+	case p.patch < 0:
+		return []comparator{{op: ">=", version: p.floor()}, {op: "<", version: bumpFloor(p.major, p.minor+1, 0)}}
+	default:
+		return []comparator{{op: "=", version: p.floor()}}
+	}
+}
+
+// primitive handles <, <=, > and >= with a possibly partial version
+func primitive(op string, p partial) []comparator {
+	if p.major < 0 {
+		if op == "<" || op == ">" {
+			return []comparator{{op: "<", version: Version{Prerelease: []string{"0"}}}} // Matches nothing
+		}
+		return []comparator{{op: ">=", version: Version{}}}
+	}
+	if p.patch >= 0 {
+		return []comparator{{op: op, version: p.floor()}}
+	}
+
+	// Round a partial bound to the edge of what it names: ">1.2" is ">=1.3.0", "<=1.2" is "<1.3.0-0"
+	next := bumpFloor(p.major+1, 0, 0)
+	if p.minor >= 0 {
+		next = bumpFloor(p.major, p.minor+1, 0)
+	}
+	switch op {
+	case ">":
+		next.Prerelease = nil
+		return []comparator{{op: ">=", version: next}}
+	case "<=":
+		return []comparator{{op: "<", version: next}}
+	case "<":
+		return []comparator{{op: "<", version: bumpFloor(p.major, max(p.minor, 0), 0)}}
+	default:
+		return []comparator{{op: ">=", version: p.floor()}}
+	}
+}
+
+// bumpFloor returns the lowest version of major.minor.patch, pre-releases included ("2.0.0-0")
+func bumpFloor(major, minor, patch int) Version {
+	return Version{Major: major, Minor: minor, Patch: patch, Prerelease: []string{"0"}}
+}
+
+// floor returns the lowest release the partial names, with wildcards as zero
+func (p partial) floor() Version {
+	return Version{Major: max(p.major, 0), Minor: max(p.minor, 0), Patch: max(p.patch, 0), Prerelease: p.prerelease}
+}
+
+// parsePartial parses "1", "1.2", "1.2.3", "1.x", "*" and pre-release versions, with an optional "v" or "=" prefix
+func parsePartial(s string) (partial, error) {
+	p := partial{major: -1, minor: -1, patch: -1}
+	raw := strings.TrimPrefix(strings.TrimPrefix(s, "="), "v")
+	if raw == "" {
+		return p, fmt.Errorf("missing version")
+	}
+
+	if idx := strings.Index(raw, "+"); idx >= 0 {
+		raw = raw[:idx]
+	}
+	if idx := strings.Index(raw, "-"); idx >= 0 {
+		if raw[idx+1:] == "" {
+			return p, fmt.Errorf("%q has an empty pre-release", s)
+		}
+		p.prerelease = strings.Split(raw[idx+1:], ".")
+		raw = raw[:idx]
+	}
+
+	parts := strings.Split(raw, ".")
+	if len(parts) > 3 {
+		return p, fmt.Errorf("%q is not a version", s)
+	}
+	numbers := []*int{&p.major, &p.minor, &p.patch}
+	for i, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			break // Everything after a wildcard is a wildcard too
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return p, fmt.Errorf("%q is not a version", s)
+		}
+		*numbers[i] = n
+	}
+
+	if p.patch < 0 {
+		p.prerelease = nil // "1.2-beta" is not a meaningful bound
+	}
+	return p, nil
+}
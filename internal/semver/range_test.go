@@ -0,0 +1,93 @@
+package semver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSatisfies(t *testing.T) {
+	tests := []struct {
+		rng      string
+		version  string
+		expected bool
+	}{
+		// Exact and x-ranges
+		{"1.2.3", "1.2.3", true},
+		{"=1.2.3", "1.2.4", false},
+		{"v1.2.3", "1.2.3", true},
+		{"1.2.x", "1.2.9", true},
+		{"1.2", "1.3.0", false},
+		{"1.x", "1.99.0", true},
+		{"1", "2.0.0", false},
+		{"*", "3.4.5", true},
+		{"", "0.0.1", true},
+		{"*", "1.0.0-beta", false},
+
+		// Caret
+		{"^1.2.3", "1.9.0", true},
+		{"^1.2.3", "2.0.0", false},
+		{"^1.2.3", "1.2.2", false},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"^0.0.3", "0.0.4", false},
+		{"^0.0", "0.0.9", true},
+		{"^0.x", "0.9.0", true},
+		{"^1.x", "1.5.0", true},
+
+		// Tilde
+		{"~1.2.3", "1.2.9", true},
+		{"~1.2.3", "1.3.0", false},
+		{"~1", "1.9.0", true},
+		{"~>1.2.3", "1.2.4", true},
+
+		// Comparators and hyphen ranges
+		{">=1.0.0 <2.0.0", "1.5.0", true},
+		{">= 1.0.0 < 2.0.0", "2.0.0", false},
+		{">1.2", "1.2.9", false},
+		{">1.2", "1.3.0", true},
+		{"<=1.2", "1.2.9", true},
+		{"<1.2", "1.1.9", true},
+		{"<1.2", "1.2.0", false},
+		{"1.2.3 - 2.3.4", "2.3.4", true},
+		{"1.2.3 - 2.3", "2.3.9", true},
+		{"1.2.3 - 2", "3.0.0", false},
+		{"1.2 - 2.3.4", "1.2.0", true},
+
+		// Alternatives
+		{"1.x || >=2.5.0", "2.6.0", true},
+		{"1.x || >=2.5.0", "2.4.0", false},
+
+		// Pre-releases only match a comparator on the same major.minor.patch
+		{"^1.2.3-beta.2", "1.2.3-beta.4", true},
+		{"^1.2.3-beta.2", "1.2.4-beta.1", false},
+		{">=1.0.0-rc.1", "1.0.0", true},
+		{"^1.0.0", "1.0.1-rc.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rng+"/"+tt.version, func(t *testing.T) {
+			ok, err := Satisfies(tt.version, tt.rng)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ok)
+		})
+	}
+}
+
+func TestParseRange_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"latest",
+		"npm:lodash@^4.17.21",
+		"file:../lib",
+		"github:user/repo",
+		"git+https://github.com/user/repo.git",
+		"workspace:*",
+		"1.2.3.4",
+	} {
+		t.Run(spec, func(t *testing.T) {
+			_, err := ParseRange(spec)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed semantic version (https://semver.org)
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease []string // Dot-separated pre-release identifiers (e.g. ["beta", "1"])
+	Build      string   // Build metadata, ignored for precedence
+}
+
+// Parse parses a version such as "1.2.3", "v1.2.3" or "1.2.3-beta.1+build.5"
+// A leading "v" or "=" is accepted because npm tooling commonly emits them.
+func Parse(s string) (Version, error) {
+	var v Version
+
+	raw := strings.TrimSpace(s)
+	raw = strings.TrimSpace(strings.TrimPrefix(raw, "="))
+	raw = strings.TrimPrefix(raw, "v")
+
+	// Split off build metadata, then pre-release
+	if idx := strings.Index(raw, "+"); idx >= 0 {
+		v.Build = raw[idx+1:]
+		raw = raw[:idx]
+	}
+	if idx := strings.Index(raw, "-"); idx >= 0 {
+		pre := raw[idx+1:]
+		if pre == "" {
+			return Version{}, fmt.Errorf("invalid version %q: empty pre-release", s)
+		}
+		v.Prerelease = strings.Split(pre, ".")
+		raw = raw[:idx]
+	}
+
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("invalid version %q: expected major.minor.patch", s)
+	}
+
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid version %q: %q is not a number", s, part)
+		}
+		numbers[i] = n
+	}
+	v.Major, v.Minor, v.Patch = numbers[0], numbers[1], numbers[2]
+
+	return v, nil
+}
+
+// String formats the version without build metadata
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 depending on whether v is lower, equal or higher than other
+func (v Version) Compare(other Version) int {
+	if c := compareInt(v.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Minor, other.Minor); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Patch, other.Patch); c != 0 {
+		return c
+	}
+	return comparePrerelease(v.Prerelease, other.Prerelease)
+}
+
+// Compare parses and compares two version strings
+func Compare(a, b string) (int, error) {
+	va, err := Parse(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := Parse(b)
+	if err != nil {
+		return 0, err
+	}
+	return va.Compare(vb), nil
+}
+
+// compareInt compares two integers
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// comparePrerelease applies the semver precedence rules for pre-release identifiers
+// A version without pre-release ranks higher than one with (1.0.0 > 1.0.0-rc.1).
+func comparePrerelease(a, b []string) int {
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return 0
+	case len(a) == 0:
+		return 1
+	case len(b) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := compareIdentifier(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInt(len(a), len(b))
+}
+
+// compareIdentifier compares one pre-release identifier
+// Numeric identifiers compare numerically and rank below alphanumeric ones.
+func compareIdentifier(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+
+	switch {
+	case errA == nil && errB == nil:
+		return compareInt(na, nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
@@ -0,0 +1,150 @@
+// Package signature verifies detached ed25519 signatures of blocklist files.
+//
+// Two signature formats are accepted:
+//
+//   - raw: the base64 encoding of a 64-byte ed25519 signature of the file
+//   - minisign: the four-line .minisig format produced by minisign(1), both
+//     legacy ("Ed") and prehashed BLAKE2b ("ED") signatures
+//
+// Trusted keys are either a base64 ed25519 public key (32 bytes) or a minisign
+// public key ("RW..."), optionally preceded by its "untrusted comment:" line.
+package signature
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// ErrNoMatchingKey means the signature was not made by any trusted key
+var ErrNoMatchingKey = errors.New("signature does not match any trusted key")
+
+// minisign algorithm tags
+const (
+	algLegacy    = "Ed" // Signs the file itself
+	algPrehashed = "ED" // Signs the BLAKE2b-512 digest of the file
+)
+
+// PublicKey is a trusted ed25519 key
+type PublicKey struct {
+	ID  []byte            // 8-byte minisign key ID (nil for raw ed25519 keys)
+	Key ed25519.PublicKey // The key itself
+}
+
+// ParseKey parses a raw base64 ed25519 key or a minisign public key
+func ParseKey(text string) (PublicKey, error) {
+	line := firstDataLine(text)
+	if line == "" {
+		return PublicKey{}, fmt.Errorf("empty public key")
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(line)
+	if err != nil {
+		return PublicKey{}, fmt.Errorf("invalid public key encoding: %w", err)
+	}
+
+	switch {
+	case len(raw) == ed25519.PublicKeySize:
+		return PublicKey{Key: ed25519.PublicKey(raw)}, nil
+	case len(raw) == 2+8+ed25519.PublicKeySize && string(raw[:2]) == algLegacy:
+		return PublicKey{ID: raw[2:10], Key: ed25519.PublicKey(raw[10:])}, nil
+	default:
+		return PublicKey{}, fmt.Errorf("unsupported public key (%d bytes; want a base64 ed25519 or minisign key)", len(raw))
+	}
+}
+
+// Verify checks a detached signature of data against the trusted keys
+func Verify(data, sig []byte, keys []PublicKey) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("no trusted keys configured")
+	}
+
+	if bytes.Contains(sig, []byte("trusted comment:")) {
+		return verifyMinisign(data, string(sig), keys)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil || len(raw) != ed25519.SignatureSize {
+		return fmt.Errorf("malformed signature: want base64 ed25519 or minisign format")
+	}
+
+	for _, key := range keys {
+		if ed25519.Verify(key.Key, data, raw) {
+			return nil
+		}
+	}
+	return ErrNoMatchingKey
+}
+
+// verifyMinisign checks a .minisig signature and its trusted comment
+func verifyMinisign(data []byte, text string, keys []PublicKey) error {
+	var sigLine, comment, globalLine string
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		switch {
+		case line == "", strings.HasPrefix(line, "untrusted comment:"):
+			continue
+		case strings.HasPrefix(line, "trusted comment:"):
+			comment = strings.TrimPrefix(strings.TrimPrefix(line, "trusted comment:"), " ")
+			if i+1 < len(lines) {
+				globalLine = strings.TrimSpace(lines[i+1])
+			}
+			i = len(lines)
+		case sigLine == "":
+			sigLine = line
+		}
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(sigLine)
+	if err != nil || len(raw) != 2+8+ed25519.SignatureSize {
+		return fmt.Errorf("malformed minisign signature")
+	}
+	alg, keyID, signature := string(raw[:2]), raw[2:10], raw[10:]
+
+	message := data
+	switch alg {
+	case algLegacy:
+	case algPrehashed:
+		digest := blake2b.Sum512(data)
+		message = digest[:]
+	default:
+		return fmt.Errorf("unsupported minisign algorithm %q", alg)
+	}
+
+	global, err := base64.StdEncoding.DecodeString(globalLine)
+	if err != nil || len(global) != ed25519.SignatureSize {
+		return fmt.Errorf("malformed minisign trusted comment signature")
+	}
+
+	for _, key := range keys {
+		if key.ID != nil && !bytes.Equal(key.ID, keyID) {
+			continue
+		}
+		if !ed25519.Verify(key.Key, message, signature) {
+			continue
+		}
+		// The trusted comment is signed together with the signature itself
+		if !ed25519.Verify(key.Key, append(append([]byte(nil), signature...), comment...), global) {
+			return fmt.Errorf("minisign trusted comment has been tampered with")
+		}
+		return nil
+	}
+	return ErrNoMatchingKey
+}
+
+// firstDataLine returns the first line that is not blank or a minisign comment
+func firstDataLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "untrusted comment:") {
+			return line
+		}
+	}
+	return ""
+}
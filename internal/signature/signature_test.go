@@ -0,0 +1,112 @@
+package signature
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+)
+
+var testKeyID = []byte{1, 2, 3, 4, 5, 6, 7, 8}
+
+// testKey derives a deterministic key pair from a seed byte
+func testKey(seed byte) ed25519.PrivateKey {
+	return ed25519.NewKeyFromSeed(bytes.Repeat([]byte{seed}, ed25519.SeedSize))
+}
+
+// minisignPublicKey encodes a public key the way minisign writes it
+func minisignPublicKey(priv ed25519.PrivateKey) string {
+	raw := append(append([]byte(algLegacy), testKeyID...), priv.Public().(ed25519.PublicKey)...)
+	return "untrusted comment: minisign public key 0807060504030201\n" + base64.StdEncoding.EncodeToString(raw) + "\n"
+}
+
+// minisignSignature signs data the way minisign does
+func minisignSignature(priv ed25519.PrivateKey, data []byte, alg, comment string) string {
+	message := data
+	if alg == algPrehashed {
+		digest := blake2b.Sum512(data)
+		message = digest[:]
+	}
+	signature := ed25519.Sign(priv, message)
+	global := ed25519.Sign(priv, append(append([]byte(nil), signature...), comment...))
+
+	raw := append(append([]byte(alg), testKeyID...), signature...)
+	return "untrusted comment: signature from minisign secret key\n" +
+		base64.StdEncoding.EncodeToString(raw) + "\n" +
+		"trusted comment: " + comment + "\n" +
+		base64.StdEncoding.EncodeToString(global) + "\n"
+}
+
+func TestParseKey(t *testing.T) {
+	priv := testKey(1)
+	pub := priv.Public().(ed25519.PublicKey)
+
+	raw, err := ParseKey(base64.StdEncoding.EncodeToString(pub))
+	require.NoError(t, err)
+	assert.Nil(t, raw.ID)
+	assert.Equal(t, pub, raw.Key)
+
+	minisign, err := ParseKey(minisignPublicKey(priv))
+	require.NoError(t, err)
+	assert.Equal(t, testKeyID, minisign.ID)
+	assert.Equal(t, pub, minisign.Key)
+
+	_, err = ParseKey("not base64!")
+	assert.Error(t, err)
+	_, err = ParseKey(base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.Error(t, err)
+}
+
+func TestVerify(t *testing.T) {
+	data := []byte("package_name,version,severity,reason,cve\nevil,1.0.0,critical,worm,\n")
+	priv := testKey(1)
+	trusted, err := ParseKey(minisignPublicKey(priv))
+	require.NoError(t, err)
+	keys := []PublicKey{trusted}
+
+	rawSig := []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, data)))
+	prehashed := []byte(minisignSignature(priv, data, algPrehashed, "timestamp:1700000000"))
+	legacy := []byte(minisignSignature(priv, data, algLegacy, "timestamp:1700000000"))
+	otherKey := []byte(minisignSignature(testKey(2), data, algPrehashed, "timestamp:1700000000"))
+	forgedComment := []byte(strings.Replace(string(prehashed), "timestamp:1700000000", "timestamp:1800000000", 1))
+
+	tests := []struct {
+		name        string
+		data        []byte
+		sig         []byte
+		expectedErr string
+	}{
+		{"raw ed25519", data, rawSig, ""},
+		{"minisign prehashed", data, prehashed, ""},
+		{"minisign legacy", data, legacy, ""},
+		{"tampered data", append([]byte("x"), data...), prehashed, ErrNoMatchingKey.Error()},
+		{"tampered data raw", append([]byte("x"), data...), rawSig, ErrNoMatchingKey.Error()},
+		{"untrusted key", data, otherKey, ErrNoMatchingKey.Error()},
+		{"forged trusted comment", data, forgedComment, "trusted comment has been tampered with"},
+		{"garbage", data, []byte("hello"), "malformed signature"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.data, tt.sig, keys)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestVerify_NoKeys(t *testing.T) {
+	err := Verify([]byte("data"), []byte("sig"), nil)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no trusted keys")
+}